@@ -26,6 +26,7 @@ package auth
 import (
 	"crypto/hmac"
 	"crypto/sha512"
+	"hash"
 
 	"github.com/kevinburke/nacl"
 )
@@ -53,3 +54,25 @@ func Verify(digest *[Size]byte, m []byte, key nacl.Key) bool {
 	expectedMAC := mac.Sum(nil) // first 256 bits of 512 bit sum
 	return hmac.Equal((*digest)[:], expectedMAC[:Size])
 }
+
+// truncated is a hash.Hash whose output is the first Size bytes of an
+// underlying HMAC-SHA-512.
+type truncated struct {
+	hash.Hash
+}
+
+func (t truncated) Size() int { return Size }
+
+func (t truncated) Sum(b []byte) []byte {
+	var sum [sha512.Size]byte
+	return append(b, t.Hash.Sum(sum[:0])[:Size]...)
+}
+
+// New returns a hash.Hash that computes the same authenticator as Sum for
+// everything written to it. Use it to authenticate a message that is too
+// large to hold in memory, or that arrives in pieces. Compare the result with
+// an expected authenticator using hmac.Equal, so as not to leak timing
+// information.
+func New(key nacl.Key) hash.Hash {
+	return truncated{hmac.New(sha512.New, key[:])}
+}
@@ -171,3 +171,22 @@ func BenchmarkAuth(b *testing.B) {
 		}
 	}
 }
+
+func TestNew(t *testing.T) {
+	for _, test := range testCases {
+		h := New(&test.key)
+		if h.Size() != Size {
+			t.Fatalf("Size: got %d, want %d", h.Size(), Size)
+		}
+		for _, b := range test.msg {
+			h.Write([]byte{b})
+		}
+		if diff := cmp.Diff(test.out[:], h.Sum(nil)); diff != "" {
+			t.Errorf("test %d: New: (-want +got)\n%s", test.num, diff)
+		}
+		prefix := []byte("prefix")
+		if got := h.Sum(prefix); string(got[:len(prefix)]) != "prefix" || len(got) != len(prefix)+Size {
+			t.Errorf("test %d: Sum did not append to its argument", test.num)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package hmacsha256 authenticates messages with HMAC-SHA-256.
+//
+// Most applications should use the auth package, which uses HMAC-SHA-512
+// truncated to 256 bits and is faster on 64-bit platforms. This package is
+// for interoperability with protocols that require HMAC-SHA-256, and is
+// interoperable with libsodium's crypto_auth_hmacsha256:
+// https://doc.libsodium.org/advanced/hmac-sha2.
+package hmacsha256 // import "github.com/kevinburke/nacl/auth/hmacsha256"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+
+	"github.com/kevinburke/nacl"
+)
+
+const (
+	// Size is the size, in bytes, of an authenticator.
+	Size = sha256.Size
+	// KeySize is the size, in bytes, of a key.
+	KeySize = nacl.KeySize
+)
+
+// NewKey returns a new random key. It panics if it cannot read enough random
+// data.
+func NewKey() nacl.Key {
+	return nacl.NewKey()
+}
+
+// Sum generates an authenticator for m using key.
+func Sum(m []byte, key nacl.Key) *[Size]byte {
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(m)
+	out := new([Size]byte)
+	mac.Sum(out[:0])
+	return out
+}
+
+// Verify reports whether digest is a valid authenticator of m under key,
+// without leaking timing information.
+func Verify(digest *[Size]byte, m []byte, key nacl.Key) bool {
+	return hmac.Equal(digest[:], Sum(m, key)[:])
+}
+
+// New returns a hash.Hash that computes the same authenticator as Sum for
+// everything written to it.
+func New(key nacl.Key) hash.Hash {
+	return hmac.New(sha256.New, key[:])
+}
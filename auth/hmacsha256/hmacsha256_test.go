@@ -0,0 +1,58 @@
+package hmacsha256
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func testMessage(n int) []byte {
+	m := make([]byte, n)
+	for i := range m {
+		m[i] = byte(i)
+	}
+	return m
+}
+
+// Generated with libsodium 1.0.18's crypto_auth_hmacsha256, with the key
+// 00 01 ... 1f.
+var golden = []struct {
+	msg []byte
+	out string
+}{
+	{nil, "d38b42096d80f45f826b44a9d5607de72496a415d3f4a1a8c88e3bb9da8dc1cb"},
+	{[]byte("hello world"), "411b9a51e8565e1fc79643b2a6c4672f4a3c3e573c33d0995a08748cb6128e8e"},
+	{testMessage(200), "c4d78316aa3de9ce6bd0b2e61c4f4dd6bdf0ec95aab84ab87fc2a11903991ad3"},
+}
+
+func TestGolden(t *testing.T) {
+	key := new([KeySize]byte)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for i, tt := range golden {
+		digest := Sum(tt.msg, key)
+		if got := hex.EncodeToString(digest[:]); got != tt.out {
+			t.Errorf("%d: Sum: got %s, want %s", i, got, tt.out)
+		}
+		if !Verify(digest, tt.msg, key) {
+			t.Errorf("%d: Verify rejected a valid authenticator", i)
+		}
+		digest[0] ^= 1
+		if Verify(digest, tt.msg, key) {
+			t.Errorf("%d: Verify accepted an invalid authenticator", i)
+		}
+
+		h := New(key)
+		h.Write(tt.msg[:len(tt.msg)/2])
+		h.Write(tt.msg[len(tt.msg)/2:])
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.out {
+			t.Errorf("%d: New: got %s, want %s", i, got, tt.out)
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	if *NewKey() == *NewKey() {
+		t.Errorf("NewKey returned the same key twice")
+	}
+}
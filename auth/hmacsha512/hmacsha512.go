@@ -0,0 +1,50 @@
+// Package hmacsha512 authenticates messages with HMAC-SHA-512.
+//
+// The authenticators are 64 bytes long. Most applications should use the auth
+// package, which truncates HMAC-SHA-512 to 32 bytes. This package is for
+// interoperability with protocols that require the full output, and is
+// interoperable with libsodium's crypto_auth_hmacsha512:
+// https://doc.libsodium.org/advanced/hmac-sha2.
+package hmacsha512 // import "github.com/kevinburke/nacl/auth/hmacsha512"
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"hash"
+
+	"github.com/kevinburke/nacl"
+)
+
+const (
+	// Size is the size, in bytes, of an authenticator.
+	Size = sha512.Size
+	// KeySize is the size, in bytes, of a key.
+	KeySize = nacl.KeySize
+)
+
+// NewKey returns a new random key. It panics if it cannot read enough random
+// data.
+func NewKey() nacl.Key {
+	return nacl.NewKey()
+}
+
+// Sum generates an authenticator for m using key.
+func Sum(m []byte, key nacl.Key) *[Size]byte {
+	mac := hmac.New(sha512.New, key[:])
+	mac.Write(m)
+	out := new([Size]byte)
+	mac.Sum(out[:0])
+	return out
+}
+
+// Verify reports whether digest is a valid authenticator of m under key,
+// without leaking timing information.
+func Verify(digest *[Size]byte, m []byte, key nacl.Key) bool {
+	return hmac.Equal(digest[:], Sum(m, key)[:])
+}
+
+// New returns a hash.Hash that computes the same authenticator as Sum for
+// everything written to it.
+func New(key nacl.Key) hash.Hash {
+	return hmac.New(sha512.New, key[:])
+}
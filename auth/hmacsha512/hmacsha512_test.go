@@ -0,0 +1,58 @@
+package hmacsha512
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func testMessage(n int) []byte {
+	m := make([]byte, n)
+	for i := range m {
+		m[i] = byte(i)
+	}
+	return m
+}
+
+// Generated with libsodium 1.0.18's crypto_auth_hmacsha512, with the key
+// 00 01 ... 1f.
+var golden = []struct {
+	msg []byte
+	out string
+}{
+	{nil, "b04a70f45e9529968060f0026344d5f4da59f1c3ce228245f6bb088d7b8aa9fc8f5f3c2a48027e48338de0fbec7d9d8fed963ae333d2b9c5704e2e95864ec78b"},
+	{[]byte("hello world"), "092397a41ed0912042c6db22500a2317b418844c41186472d4b00d3e5dabdbedca7592694d47af298e7162ebbaf87e5dd6da221b46bb059b7b2432fa74717ced"},
+	{testMessage(200), "c0051d021d3d1b61041a6d01c75c6118ebb92bf6bfae9c2dc7026a51a16078b6487fdeda16467bd21de111898107671898f21192e63373c91067e0c65d5fd2fe"},
+}
+
+func TestGolden(t *testing.T) {
+	key := new([KeySize]byte)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for i, tt := range golden {
+		digest := Sum(tt.msg, key)
+		if got := hex.EncodeToString(digest[:]); got != tt.out {
+			t.Errorf("%d: Sum: got %s, want %s", i, got, tt.out)
+		}
+		if !Verify(digest, tt.msg, key) {
+			t.Errorf("%d: Verify rejected a valid authenticator", i)
+		}
+		digest[0] ^= 1
+		if Verify(digest, tt.msg, key) {
+			t.Errorf("%d: Verify accepted an invalid authenticator", i)
+		}
+
+		h := New(key)
+		h.Write(tt.msg[:len(tt.msg)/2])
+		h.Write(tt.msg[len(tt.msg)/2:])
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.out {
+			t.Errorf("%d: New: got %s, want %s", i, got, tt.out)
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	if *NewKey() == *NewKey() {
+		t.Errorf("NewKey returned the same key twice")
+	}
+}
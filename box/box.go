@@ -13,23 +13,34 @@ example, by using nonce 1 for the first message, nonce 2 for the second
 message, etc. Nonces are long enough that randomly generated nonces have
 negligible risk of collision.
 
+SealAnonymous and OpenAnonymous implement libsodium's sealed boxes, which
+let a sender encrypt a message for a recipient without a long-term key pair of
+their own: https://doc.libsodium.org/public-key_cryptography/sealed_boxes.
+
 This package is interoperable with NaCl: https://nacl.cr.yp.to/box.html.
 */
 package box // import "github.com/kevinburke/nacl/box"
 
 import (
+	"crypto/sha512"
 	"errors"
 	"io"
 
 	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/onetimeauth"
 	"github.com/kevinburke/nacl/scalarmult"
 	"github.com/kevinburke/nacl/secretbox"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/salsa20/salsa"
 )
 
 // Overhead is the number of bytes of overhead when boxing a message.
 const Overhead = secretbox.Overhead
 
+// AnonymousOverhead is the number of bytes of overhead when sealing a message
+// with SealAnonymous: an ephemeral public key plus Overhead.
+const AnonymousOverhead = nacl.KeySize + Overhead
+
 // GenerateKey generates a new public/private key pair suitable for use with
 // Seal and Open.
 func GenerateKey(rand io.Reader) (publicKey, privateKey nacl.Key, err error) {
@@ -45,6 +56,17 @@ func GenerateKey(rand io.Reader) (publicKey, privateKey nacl.Key, err error) {
 	return publicKey, privateKey, nil
 }
 
+// KeyPairFromSeed deterministically derives a public/private key pair from
+// seed. The private key is the first 32 bytes of the SHA-512 hash of the seed,
+// matching libsodium's crypto_box_seed_keypair. The seed must be kept as
+// secret as the private key.
+func KeyPairFromSeed(seed nacl.Key) (publicKey, privateKey nacl.Key) {
+	h := sha512.Sum512(seed[:])
+	privateKey = new([nacl.KeySize]byte)
+	copy(privateKey[:], h[:nacl.KeySize])
+	return scalarmult.Base(privateKey), privateKey
+}
+
 var zeros [16]byte
 
 // Precompute calculates the shared key between peersPublicKey and privateKey
@@ -57,6 +79,18 @@ func Precompute(peersPublicKey, privateKey nacl.Key) nacl.Key {
 	return sharedKey
 }
 
+// PrecomputeChecked is like Precompute, but returns an error if
+// peersPublicKey is a point of small order. Precompute derives a shared key
+// that an attacker can predict from such a key, without knowing privateKey.
+func PrecomputeChecked(peersPublicKey, privateKey nacl.Key) (nacl.Key, error) {
+	sharedKey, err := scalarmult.MultChecked(privateKey, peersPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	salsa.HSalsa20(sharedKey, &zeros, sharedKey, &salsa.Sigma)
+	return sharedKey, nil
+}
+
 // EasySeal encrypts message using peersPublicKey and privateKey. The output
 // will have a randomly generated nonce prepended to it. The output will be
 // Overhead + 24 bytes longer than the original.
@@ -73,12 +107,38 @@ func Seal(out, message []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl
 	return secretbox.Seal(out, message, nonce, sharedKey)
 }
 
+// SealChecked is like Seal, but returns an error if peersPublicKey is a point
+// of small order. See PrecomputeChecked.
+func SealChecked(out, message []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, error) {
+	sharedKey, err := PrecomputeChecked(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return secretbox.Seal(out, message, nonce, sharedKey), nil
+}
+
 // SealAfterPrecomputation performs the same actions as Seal, but takes a
 // shared key as generated by Precompute.
 func SealAfterPrecomputation(out, message []byte, nonce nacl.Nonce, sharedKey nacl.Key) []byte {
 	return secretbox.Seal(out, message, nonce, sharedKey)
 }
 
+// SealDetached encrypts message and appends the ciphertext to out, which must
+// not overlap message. Unlike Seal, the authenticator is returned separately
+// rather than being prepended to the ciphertext, so the appended ciphertext is
+// the same length as message. The nonce must be unique for each distinct
+// message for a given pair of keys.
+func SealDetached(out, message []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, *[onetimeauth.Size]byte) {
+	sharedKey := Precompute(peersPublicKey, privateKey)
+	return secretbox.SealDetached(out, message, nonce, sharedKey)
+}
+
+// SealDetachedAfterPrecomputation performs the same actions as SealDetached,
+// but takes a shared key as generated by Precompute.
+func SealDetachedAfterPrecomputation(out, message []byte, nonce nacl.Nonce, sharedKey nacl.Key) ([]byte, *[onetimeauth.Size]byte) {
+	return secretbox.SealDetached(out, message, nonce, sharedKey)
+}
+
 var errInvalidInput = errors.New("box: Could not decrypt invalid input")
 
 // EasyOpen decrypts box using key. We assume a 24-byte nonce is prepended to
@@ -105,8 +165,83 @@ func Open(out, box []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key
 	return secretbox.Open(out, box, nonce, sharedKey)
 }
 
+// OpenChecked is like Open, but returns an error if peersPublicKey is a point
+// of small order (see PrecomputeChecked), or if box can't be authenticated.
+func OpenChecked(out, box []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, error) {
+	sharedKey, err := PrecomputeChecked(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	opened, ok := secretbox.Open(out, box, nonce, sharedKey)
+	if !ok {
+		return nil, errInvalidInput
+	}
+	return opened, nil
+}
+
 // OpenAfterPrecomputation performs the same actions as Open, but takes a
 // shared key as generated by Precompute.
 func OpenAfterPrecomputation(out, box []byte, nonce nacl.Nonce, sharedKey nacl.Key) ([]byte, bool) {
 	return secretbox.Open(out, box, nonce, sharedKey)
 }
+
+// OpenDetached authenticates and decrypts a ciphertext and authenticator
+// produced by SealDetached and appends the message to out, which must not
+// overlap ciphertext. The output will be the same length as ciphertext.
+func OpenDetached(out, ciphertext []byte, tag *[onetimeauth.Size]byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, bool) {
+	sharedKey := Precompute(peersPublicKey, privateKey)
+	return secretbox.OpenDetached(out, ciphertext, tag, nonce, sharedKey)
+}
+
+// OpenDetachedAfterPrecomputation performs the same actions as OpenDetached,
+// but takes a shared key as generated by Precompute.
+func OpenDetachedAfterPrecomputation(out, ciphertext []byte, tag *[onetimeauth.Size]byte, nonce nacl.Nonce, sharedKey nacl.Key) ([]byte, bool) {
+	return secretbox.OpenDetached(out, ciphertext, tag, nonce, sharedKey)
+}
+
+// anonymousNonce returns the nonce for a sealed box, BLAKE2b-192 of the
+// ephemeral public key followed by the recipient's public key.
+func anonymousNonce(ephemeralPublicKey, recipientPublicKey nacl.Key) nacl.Nonce {
+	h, err := blake2b.New(nacl.NonceSize, nil)
+	if err != nil {
+		panic(err)
+	}
+	h.Write(ephemeralPublicKey[:])
+	h.Write(recipientPublicKey[:])
+	nonce := new([nacl.NonceSize]byte)
+	h.Sum(nonce[:0])
+	return nonce
+}
+
+// SealAnonymous encrypts message so that only the owner of
+// recipientPublicKey can decrypt it. The sender is anonymous: a new key pair
+// is generated for every message and the private key is discarded, so the
+// recipient can't verify who sent the message, and the sender can't decrypt
+// it after sealing it. The output is AnonymousOverhead bytes longer than
+// message, and consists of the ephemeral public key followed by the box.
+//
+// SealAnonymous panics if it cannot read enough random data.
+func SealAnonymous(message []byte, recipientPublicKey nacl.Key) []byte {
+	ephemeralPrivateKey := nacl.NewKey()
+	ephemeralPublicKey := scalarmult.Base(ephemeralPrivateKey)
+	nonce := anonymousNonce(ephemeralPublicKey, recipientPublicKey)
+	sealed := Seal(ephemeralPublicKey[:], message, nonce, recipientPublicKey, ephemeralPrivateKey)
+	clear(ephemeralPrivateKey[:])
+	return sealed
+}
+
+// OpenAnonymous decrypts a message produced by SealAnonymous, using the
+// recipient's key pair.
+func OpenAnonymous(sealed []byte, publicKey, privateKey nacl.Key) ([]byte, error) {
+	if len(sealed) < AnonymousOverhead {
+		return nil, errors.New("box: message too short")
+	}
+	ephemeralPublicKey := new([nacl.KeySize]byte)
+	copy(ephemeralPublicKey[:], sealed[:nacl.KeySize])
+	nonce := anonymousNonce(ephemeralPublicKey, publicKey)
+	decrypted, ok := Open([]byte{}, sealed[nacl.KeySize:], nonce, ephemeralPublicKey, privateKey)
+	if !ok {
+		return nil, errInvalidInput
+	}
+	return decrypted, nil
+}
@@ -105,3 +105,181 @@ func TestBox(t *testing.T) {
 		t.Fatalf("box didn't match, got\n%x\n, expected\n%x", box, expected)
 	}
 }
+
+func TestSealOpenAnonymous(t *testing.T) {
+	publicKey, privateKey, _ := GenerateKey(rand.Reader)
+	otherPublicKey, otherPrivateKey, _ := GenerateKey(rand.Reader)
+	message := []byte("test message")
+
+	sealed := SealAnonymous(message, publicKey)
+	if len(sealed) != len(message)+AnonymousOverhead {
+		t.Errorf("got sealed length %d, want %d", len(sealed), len(message)+AnonymousOverhead)
+	}
+	opened, err := OpenAnonymous(sealed, publicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to open sealed box: %v", err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("got %x, want %x", opened, message)
+	}
+	if _, err := OpenAnonymous(sealed, otherPublicKey, otherPrivateKey); err == nil {
+		t.Errorf("opened sealed box with the wrong key pair")
+	}
+	for i := range sealed {
+		sealed[i] ^= 0x40
+		if _, err := OpenAnonymous(sealed, publicKey, privateKey); err == nil {
+			t.Fatalf("opened sealed box with byte %d corrupted", i)
+		}
+		sealed[i] ^= 0x40
+	}
+	if _, err := OpenAnonymous(sealed[:AnonymousOverhead-1], publicKey, privateKey); err == nil {
+		t.Errorf("opened truncated sealed box")
+	}
+}
+
+func TestKeyPairFromSeed(t *testing.T) {
+	var seed [32]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	publicKey, privateKey := KeyPairFromSeed(&seed)
+	// Generated with libsodium's crypto_box_seed_keypair.
+	if got := hex.EncodeToString(publicKey[:]); got != "4701d08488451f545a409fb58ae3e58581ca40ac3f7f114698cd71deac73ca01" {
+		t.Errorf("public key: got %s", got)
+	}
+	if got := hex.EncodeToString(privateKey[:]); got != "3d94eea49c580aef816935762be049559d6d1440dede12e6a125f1841fff8e6f" {
+		t.Errorf("private key: got %s", got)
+	}
+}
+
+func TestOpenAnonymousLibsodium(t *testing.T) {
+	// Generated with libsodium's crypto_box_seal, for the key pair produced by
+	// crypto_box_seed_keypair with the seed 0x00..0x1f.
+	var publicKey, privateKey [32]byte
+	pk, _ := hex.DecodeString("4701d08488451f545a409fb58ae3e58581ca40ac3f7f114698cd71deac73ca01")
+	sk, _ := hex.DecodeString("3d94eea49c580aef816935762be049559d6d1440dede12e6a125f1841fff8e6f")
+	copy(publicKey[:], pk)
+	copy(privateKey[:], sk)
+
+	tests := []struct {
+		sealed string
+		want   string
+	}{
+		{"7dfd728bc07835ded43915a95a771afd7b2ab2e6322d227a483756db205da166942c942ada71817064c2fa1ea8576a81", ""},
+		{"c5a1849f14018ff6f67654b40dc2903ad535282cc19b0e04e2350ee5af20c408f2b02dcc5985a42cebc4ff9ed65d6a6d54743b92710984bc95135e", "hello world"},
+	}
+	for i, tt := range tests {
+		sealed, _ := hex.DecodeString(tt.sealed)
+		opened, err := OpenAnonymous(sealed, &publicKey, &privateKey)
+		if err != nil {
+			t.Fatalf("%d: failed to open sealed box: %v", i, err)
+		}
+		if string(opened) != tt.want {
+			t.Errorf("%d: got %q, want %q", i, opened, tt.want)
+		}
+	}
+}
+
+func TestSealOpenDetached(t *testing.T) {
+	var privateKey1, privateKey2 [32]byte
+	for i := range privateKey1[:] {
+		privateKey1[i] = 1
+	}
+	for i := range privateKey2[:] {
+		privateKey2[i] = 2
+	}
+	publicKey1 := scalarmult.Base(&privateKey1)
+	publicKey2 := scalarmult.Base(&privateKey2)
+	message := bytes.Repeat([]byte{3}, 64)
+	var nonce [24]byte
+	for i := range nonce[:] {
+		nonce[i] = 4
+	}
+
+	ciphertext, tag := SealDetached(nil, message, &nonce, publicKey1, &privateKey2)
+	// expected was generated using libsodium's crypto_box_detached.
+	expectedTag, _ := hex.DecodeString("78ea30b19d2341ebbdba54180f821eec")
+	expected, _ := hex.DecodeString("265cf86312549bea8a37652a8bb94f07b78a73ed1708085e6ddd0e943bbdeb8755079a37eb31d86163ce241164a47629c0539f330b4914cd135b3855bc2a2dfc")
+	if !bytes.Equal(tag[:], expectedTag) {
+		t.Errorf("tag didn't match, got\n%x\n, expected\n%x", tag, expectedTag)
+	}
+	if !bytes.Equal(ciphertext, expected) {
+		t.Fatalf("ciphertext didn't match, got\n%x\n, expected\n%x", ciphertext, expected)
+	}
+
+	opened, ok := OpenDetached(nil, ciphertext, tag, &nonce, publicKey2, &privateKey1)
+	if !ok {
+		t.Fatalf("failed to open detached box")
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("got %x, want %x", opened, message)
+	}
+
+	sharedKey := Precompute(publicKey1, &privateKey2)
+	ciphertext2, tag2 := SealDetachedAfterPrecomputation(nil, message, &nonce, sharedKey)
+	if !bytes.Equal(ciphertext2, ciphertext) || *tag2 != *tag {
+		t.Errorf("SealDetachedAfterPrecomputation does not match SealDetached")
+	}
+	sharedKey = Precompute(publicKey2, &privateKey1)
+	if _, ok := OpenDetachedAfterPrecomputation(nil, ciphertext, tag, &nonce, sharedKey); !ok {
+		t.Errorf("failed to open detached box after precomputation")
+	}
+	tag[0] ^= 0x40
+	if _, ok := OpenDetachedAfterPrecomputation(nil, ciphertext, tag, &nonce, sharedKey); ok {
+		t.Errorf("opened detached box with corrupted tag")
+	}
+}
+
+func TestChecked(t *testing.T) {
+	publicKey1, privateKey1, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey2, privateKey2, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedKey, err := PrecomputeChecked(publicKey2, privateKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *sharedKey != *Precompute(publicKey2, privateKey1) {
+		t.Errorf("PrecomputeChecked does not match Precompute")
+	}
+
+	var nonce [24]byte
+	message := []byte("hello world")
+	box, err := SealChecked(nil, message, &nonce, publicKey2, privateKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Seal(nil, message, &nonce, publicKey2, privateKey1); !bytes.Equal(box, want) {
+		t.Errorf("SealChecked does not match Seal")
+	}
+	opened, err := OpenChecked(nil, box, &nonce, publicKey1, privateKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Errorf("got %q, want %q", opened, message)
+	}
+	box[0] ^= 1
+	if _, err := OpenChecked(nil, box, &nonce, publicKey1, privateKey2); err == nil {
+		t.Errorf("OpenChecked accepted a corrupted box")
+	}
+
+	// The shared key for a point of order 8 is predictable.
+	lowOrder := new([32]byte)
+	b, _ := hex.DecodeString("e0eb7a7c3b41b8ae1656e3faf19fc46ada098deb9c32b1fd866205165f49b800")
+	copy(lowOrder[:], b)
+	if _, err := PrecomputeChecked(lowOrder, privateKey1); err == nil {
+		t.Errorf("PrecomputeChecked accepted a low order point")
+	}
+	if _, err := SealChecked(nil, message, &nonce, lowOrder, privateKey1); err == nil {
+		t.Errorf("SealChecked accepted a low order point")
+	}
+	box = Seal(nil, message, &nonce, lowOrder, privateKey1)
+	if _, err := OpenChecked(nil, box, &nonce, lowOrder, privateKey2); err == nil {
+		t.Errorf("OpenChecked accepted a low order point")
+	}
+}
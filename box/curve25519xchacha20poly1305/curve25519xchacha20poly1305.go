@@ -0,0 +1,139 @@
+/*
+Package curve25519xchacha20poly1305 authenticates and encrypts messages using
+public-key cryptography.
+
+It is an alternative to the box package, with a similar API, that uses
+Curve25519, XChaCha20 and Poly1305 to encrypt and authenticate messages. The
+shared key is derived with HChaCha20 instead of HSalsa20. The length of
+messages is not hidden.
+
+Unlike the box package, a peer's public key of small order is always
+rejected, as libsodium does, so Precompute, Seal and EasySeal return an
+error.
+
+It is the caller's responsibility to ensure the uniqueness of nonces—for
+example, by using nonce 1 for the first message, nonce 2 for the second
+message, etc. Nonces are long enough that randomly generated nonces have
+negligible risk of collision.
+
+This package is interoperable with libsodium's
+crypto_box_curve25519xchacha20poly1305:
+https://doc.libsodium.org/public-key_cryptography/authenticated_encryption#alternative-api.
+*/
+package curve25519xchacha20poly1305 // import "github.com/kevinburke/nacl/box/curve25519xchacha20poly1305"
+
+import (
+	"errors"
+	"io"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/scalarmult"
+	"github.com/kevinburke/nacl/secretbox/xchacha20poly1305"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Overhead is the number of bytes of overhead when boxing a message.
+const Overhead = xchacha20poly1305.Overhead
+
+// GenerateKey generates a new public/private key pair suitable for use with
+// Seal and Open.
+func GenerateKey(rand io.Reader) (publicKey, privateKey nacl.Key, err error) {
+	privateKey = new([32]byte)
+	_, err = io.ReadFull(rand, privateKey[:])
+	if err != nil {
+		publicKey = nil
+		privateKey = nil
+		return
+	}
+
+	publicKey = scalarmult.Base(privateKey)
+	return publicKey, privateKey, nil
+}
+
+var zeros [16]byte
+
+// Precompute calculates the shared key between peersPublicKey and privateKey.
+// The shared key can be used with OpenAfterPrecomputation and
+// SealAfterPrecomputation to speed up processing when using the same pair of
+// keys repeatedly. An error is returned if peersPublicKey is a point of small
+// order, from which an attacker could predict the shared key.
+func Precompute(peersPublicKey, privateKey nacl.Key) (nacl.Key, error) {
+	shared, err := scalarmult.MultChecked(privateKey, peersPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	subKey, err := chacha20.HChaCha20(shared[:], zeros[:])
+	if err != nil {
+		panic(err)
+	}
+	sharedKey := new([nacl.KeySize]byte)
+	copy(sharedKey[:], subKey)
+	return sharedKey, nil
+}
+
+// EasySeal encrypts message using peersPublicKey and privateKey. The output
+// will have a randomly generated nonce prepended to it. The output will be
+// Overhead + 24 bytes longer than the original. An error is returned if
+// peersPublicKey is a point of small order.
+func EasySeal(message []byte, peersPublicKey, privateKey nacl.Key) ([]byte, error) {
+	nonce := nacl.NewNonce()
+	return Seal(nonce[:], message, nonce, peersPublicKey, privateKey)
+}
+
+// Seal appends an encrypted and authenticated copy of message to out, which
+// will be Overhead bytes longer than the original and must not overlap. The
+// nonce must be unique for each distinct message for a given pair of keys.
+// An error is returned if peersPublicKey is a point of small order.
+func Seal(out, message []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, error) {
+	sharedKey, err := Precompute(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return xchacha20poly1305.Seal(out, message, nonce, sharedKey), nil
+}
+
+// SealAfterPrecomputation performs the same actions as Seal, but takes a
+// shared key as generated by Precompute.
+func SealAfterPrecomputation(out, message []byte, nonce nacl.Nonce, sharedKey nacl.Key) []byte {
+	return xchacha20poly1305.Seal(out, message, nonce, sharedKey)
+}
+
+var errInvalidInput = errors.New("curve25519xchacha20poly1305: Could not decrypt invalid input")
+
+// EasyOpen decrypts box using key. We assume a 24-byte nonce is prepended to
+// the encrypted text in box. The key and nonce pair must be unique for each
+// distinct message.
+func EasyOpen(box []byte, peersPublicKey, privateKey nacl.Key) ([]byte, error) {
+	if len(box) < 24 {
+		return nil, errors.New("curve25519xchacha20poly1305: message too short")
+	}
+	sharedKey, err := Precompute(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	decryptNonce := new([24]byte)
+	copy(decryptNonce[:], box[:24])
+	decrypted, ok := OpenAfterPrecomputation([]byte{}, box[24:], decryptNonce, sharedKey)
+	if !ok {
+		return nil, errInvalidInput
+	}
+	return decrypted, nil
+}
+
+// Open authenticates and decrypts a box produced by Seal and appends the
+// message to out, which must not overlap box. The output will be Overhead
+// bytes smaller than box. Open returns false if peersPublicKey is a point of
+// small order.
+func Open(out, box []byte, nonce nacl.Nonce, peersPublicKey, privateKey nacl.Key) ([]byte, bool) {
+	sharedKey, err := Precompute(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, false
+	}
+	return xchacha20poly1305.Open(out, box, nonce, sharedKey)
+}
+
+// OpenAfterPrecomputation performs the same actions as Open, but takes a
+// shared key as generated by Precompute.
+func OpenAfterPrecomputation(out, box []byte, nonce nacl.Nonce, sharedKey nacl.Key) ([]byte, bool) {
+	return xchacha20poly1305.Open(out, box, nonce, sharedKey)
+}
@@ -0,0 +1,135 @@
+package curve25519xchacha20poly1305
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kevinburke/nacl/scalarmult"
+	"golang.org/x/crypto/chacha20"
+)
+
+func TestEasySealOpen(t *testing.T) {
+	publicKey1, privateKey1, _ := GenerateKey(rand.Reader)
+	publicKey2, privateKey2, _ := GenerateKey(rand.Reader)
+
+	if *privateKey1 == *privateKey2 {
+		t.Fatalf("private keys are equal!")
+	}
+	if *publicKey1 == *publicKey2 {
+		t.Fatalf("public keys are equal!")
+	}
+	message := []byte("test message")
+
+	box, err := EasySeal(message, publicKey1, privateKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := EasyOpen(box, publicKey2, privateKey1)
+	if err != nil {
+		t.Fatalf("failed to open box: %v", err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("got %x, want %x", opened, message)
+	}
+	for i := range box {
+		box[i] ^= 0x40
+		_, err := EasyOpen(box, publicKey2, privateKey1)
+		if err == nil {
+			t.Fatalf("opened box with byte %d corrupted", i)
+		}
+		box[i] ^= 0x40
+	}
+}
+
+func TestBox(t *testing.T) {
+	var privateKey1, privateKey2 [32]byte
+	for i := range privateKey1[:] {
+		privateKey1[i] = 1
+	}
+	for i := range privateKey2[:] {
+		privateKey2[i] = 2
+	}
+
+	publicKey1 := scalarmult.Base(&privateKey1)
+	publicKey2 := scalarmult.Base(&privateKey2)
+	message := bytes.Repeat([]byte{3}, 100)
+
+	var nonce [24]byte
+	for i := range nonce[:] {
+		nonce[i] = 4
+	}
+
+	// expected values were generated using libsodium's
+	// crypto_box_curve25519xchacha20poly1305_beforenm and
+	// crypto_box_curve25519xchacha20poly1305_easy.
+	sharedKey, err := Precompute(publicKey1, &privateKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKey, _ := hex.DecodeString("5c473e682845f716fcefb486fbdcf9a87cd79af7db438e2a7d218c82e2741c01")
+	if !bytes.Equal(sharedKey[:], expectedKey) {
+		t.Errorf("shared key didn't match, got\n%x\n, expected\n%x", sharedKey, expectedKey)
+	}
+
+	box, err := Seal(nil, message, &nonce, publicKey1, &privateKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := hex.DecodeString("e9cd813bc782802173ccf8ad443c34af7f911c01b9e940395713c6606faadee6a018260d7c1199805e5f7c32110421b5f2beb11d988763d84380d5412a0dcf3c42570b68052f0ba5c0d539f580a118d520e9051061cdef946037c2139b157925c7c0f68b16af4d7a508ec2e16000010c80c0125d")
+	if !bytes.Equal(box, expected) {
+		t.Fatalf("box didn't match, got\n%x\n, expected\n%x", box, expected)
+	}
+
+	opened, ok := Open(nil, box, &nonce, publicKey2, &privateKey1)
+	if !ok {
+		t.Fatalf("failed to open box")
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("got %x, want %x", opened, message)
+	}
+	sharedKey, err = Precompute(publicKey2, &privateKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, ok = OpenAfterPrecomputation(nil, box, &nonce, sharedKey)
+	if !ok || !bytes.Equal(opened, message) {
+		t.Fatalf("failed to open box after precomputation")
+	}
+}
+
+func TestLowOrder(t *testing.T) {
+	publicKey, privateKey, _ := GenerateKey(rand.Reader)
+	// A point of order 8; multiplying it by any clamped scalar gives zero.
+	lowOrder := new([32]byte)
+	b, _ := hex.DecodeString("e0eb7a7c3b41b8ae1656e3faf19fc46ada098deb9c32b1fd866205165f49b800")
+	copy(lowOrder[:], b)
+
+	if _, err := Precompute(lowOrder, privateKey); err == nil {
+		t.Errorf("Precompute accepted a low order point")
+	}
+	var nonce [24]byte
+	if _, err := Seal(nil, []byte("test message"), &nonce, lowOrder, privateKey); err == nil {
+		t.Errorf("Seal accepted a low order point")
+	}
+	if _, err := EasySeal([]byte("test message"), lowOrder, privateKey); err == nil {
+		t.Errorf("EasySeal accepted a low order point")
+	}
+
+	// A box sealed under the all-zero shared secret a low order point
+	// produces must not open either.
+	zeroKey, _ := chacha20.HChaCha20(make([]byte, 32), make([]byte, 16))
+	sharedKey := new([32]byte)
+	copy(sharedKey[:], zeroKey)
+	box := SealAfterPrecomputation(nil, []byte("test message"), &nonce, sharedKey)
+	if _, ok := Open(nil, box, &nonce, lowOrder, privateKey); ok {
+		t.Errorf("Open accepted a low order point")
+	}
+	if _, err := EasyOpen(append(nonce[:], box...), lowOrder, privateKey); err == nil {
+		t.Errorf("EasyOpen accepted a low order point")
+	}
+	if _, err := Precompute(publicKey, privateKey); err != nil {
+		t.Errorf("Precompute rejected a valid public key: %v", err)
+	}
+}
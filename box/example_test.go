@@ -81,3 +81,23 @@ func Example_precompute() {
 	fmt.Println(string(decrypted))
 	// Output: A fellow of infinite jest, of most excellent fancy
 }
+
+func Example_sealAnonymous() {
+	recipientPublicKey, recipientPrivateKey, err := box.GenerateKey(crypto_rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	// The sender only needs the recipient's public key. The output contains
+	// a freshly generated public key, and can only be decrypted by the
+	// recipient.
+	msg := []byte("Good night, sweet prince")
+	sealed := box.SealAnonymous(msg, recipientPublicKey)
+
+	decrypted, err := box.OpenAnonymous(sealed, recipientPublicKey, recipientPrivateKey)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(decrypted))
+	// Output: Good night, sweet prince
+}
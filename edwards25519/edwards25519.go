@@ -0,0 +1,262 @@
+/*
+Package edwards25519 implements arithmetic on points of the edwards25519
+curve, the twisted Edwards form of Curve25519 used by Ed25519, and on scalars
+modulo the order of its prime-order subgroup.
+
+These are low-level building blocks, for protocols such as blind key
+derivation that need more than the sign and scalarmult packages offer. Most
+applications should use those packages, or ristretto255, whose elements don't
+have the small-order components that make raw edwards25519 points easy to
+misuse. Points and scalars are passed around as 32-byte arrays.
+
+This package is interoperable with libsodium's crypto_core_ed25519 and
+crypto_scalarmult_ed25519:
+https://doc.libsodium.org/advanced/point-arithmetic.
+*/
+package edwards25519 // import "github.com/kevinburke/nacl/edwards25519"
+
+import (
+	"bytes"
+	"errors"
+
+	edwards "filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/kevinburke/nacl/internal/scalar"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+const (
+	// Size is the size, in bytes, of an encoded point.
+	Size = 32
+	// UniformSize is the size, in bytes, of the input to FromUniform.
+	UniformSize = 32
+	// HashSize is the size, in bytes, of the input to FromHash.
+	HashSize = 64
+	// ScalarSize is the size, in bytes, of a scalar.
+	ScalarSize = scalar.Size
+	// NonReducedScalarSize is the size, in bytes, of the input to
+	// ScalarReduce.
+	NonReducedScalarSize = scalar.WideSize
+)
+
+var (
+	errInvalidPoint = errors.New("edwards25519: invalid point")
+	errIdentity     = errors.New("edwards25519: result is the identity point")
+)
+
+var (
+	identity = edwards.NewIdentityPoint()
+	minusOne = edwards.NewScalar().Negate(scalar.New(&[ScalarSize]byte{0: 1}))
+
+	one = new(field.Element).One()
+	// curveA is the Montgomery curve constant A = 486662.
+	curveA = new(field.Element).Mult32(one, 486662)
+)
+
+// decode decodes p, accepting any encoding of a point on the curve, the same
+// way libsodium's crypto_core_ed25519_add does.
+func decode(p *[Size]byte) (*edwards.Point, error) {
+	q, err := new(edwards.Point).SetBytes(p[:])
+	if err != nil {
+		return nil, errInvalidPoint
+	}
+	return q, nil
+}
+
+// decodeChecked decodes p, and checks that it is the canonical encoding of a
+// point in the prime-order subgroup other than the identity.
+func decodeChecked(p *[Size]byte) (*edwards.Point, error) {
+	q, err := decode(p)
+	if err != nil || !bytes.Equal(q.Bytes(), p[:]) {
+		return nil, errInvalidPoint
+	}
+	if new(edwards.Point).MultByCofactor(q).Equal(identity) == 1 {
+		return nil, errInvalidPoint
+	}
+	// [L]Q = [L-1]Q + Q is the identity exactly when Q has order L.
+	r := new(edwards.Point).ScalarMult(minusOne, q)
+	if r.Add(r, q).Equal(identity) != 1 {
+		return nil, errInvalidPoint
+	}
+	return q, nil
+}
+
+func encode(p *edwards.Point) *[Size]byte {
+	out := new([Size]byte)
+	copy(out[:], p.Bytes())
+	return out
+}
+
+// IsValidPoint reports whether p is the canonical encoding of a point in the
+// prime-order subgroup, and does not have small order.
+func IsValidPoint(p *[Size]byte) bool {
+	_, err := decodeChecked(p)
+	return err == nil
+}
+
+// Add returns the sum of the points p and q. It returns an error if either
+// is not on the curve. Unlike IsValidPoint, Add accepts points that are of
+// small order or outside the prime-order subgroup.
+func Add(p, q *[Size]byte) (*[Size]byte, error) {
+	pp, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+	qq, err := decode(q)
+	if err != nil {
+		return nil, err
+	}
+	return encode(pp.Add(pp, qq)), nil
+}
+
+// Sub returns the difference of the points p and q. It returns an error if
+// either is not on the curve. Unlike IsValidPoint, Sub accepts points that
+// are of small order or outside the prime-order subgroup.
+func Sub(p, q *[Size]byte) (*[Size]byte, error) {
+	pp, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+	qq, err := decode(q)
+	if err != nil {
+		return nil, err
+	}
+	return encode(pp.Subtract(pp, qq)), nil
+}
+
+// elligator2 maps r to a point in the prime-order subgroup, using the
+// Elligator 2 map to Curve25519 and the birational map to edwards25519. The
+// sign of the resulting x coordinate is xSign; the result is then multiplied
+// by the cofactor.
+func elligator2(r *field.Element, xSign byte) *edwards.Point {
+	// x = -A / (1 + 2r^2)
+	x := new(field.Element).Square(r)
+	x.Add(x, x)
+	x.Add(x, one)
+	x.Invert(x)
+	x.Multiply(x, curveA)
+	x.Negate(x)
+
+	// If e = x^3 + Ax^2 + x is not square, use -x - A instead.
+	x2 := new(field.Element).Square(x)
+	e := new(field.Element).Multiply(x, x2)
+	e.Add(e, x)
+	e.Add(e, x2.Multiply(x2, curveA))
+	_, isSquare := new(field.Element).SqrtRatio(e, one)
+	negX := new(field.Element).Negate(x)
+	negX.Subtract(negX, curveA)
+	x.Select(x, negX, isSquare)
+
+	// y = (x - 1) / (x + 1)
+	y := new(field.Element).Add(x, one)
+	y.Invert(y)
+	y.Multiply(y, new(field.Element).Subtract(x, one))
+
+	s := y.Bytes()
+	s[31] |= xSign
+	p, err := new(edwards.Point).SetBytes(s)
+	if err != nil {
+		panic("edwards25519: internal error: Elligator 2 produced an invalid point")
+	}
+	return p.MultByCofactor(p)
+}
+
+// FromUniform maps 32 bytes to a point in the prime-order subgroup, with the
+// most significant bit of r selecting the sign of the x coordinate. It
+// matches libsodium's crypto_core_ed25519_from_uniform.
+//
+// The result is not uniformly distributed; use FromHash to hash arbitrary
+// data to a point.
+func FromUniform(r *[UniformSize]byte) *[Size]byte {
+	fe, err := new(field.Element).SetBytes(r[:])
+	if err != nil {
+		panic(err)
+	}
+	return encode(elligator2(fe, r[31]&0x80))
+}
+
+// FromHash maps 64 bytes, typically the output of a hash function, to a point
+// in the prime-order subgroup. It matches libsodium's
+// crypto_core_ed25519_from_hash: h is read as a big-endian integer, with its
+// most significant bit selecting the sign of the x coordinate, and reduced
+// modulo 2^255-19 before being passed through the Elligator 2 map.
+func FromHash(h *[HashSize]byte) *[Size]byte {
+	var le [HashSize]byte
+	for i := range le {
+		le[i] = h[HashSize-1-i]
+	}
+	le[HashSize-1] &= 0x7f
+	fe, err := new(field.Element).SetWideBytes(le[:])
+	if err != nil {
+		panic(err)
+	}
+	return encode(elligator2(fe, h[0]&0x80))
+}
+
+// Random returns a random point in the prime-order subgroup. It panics if it
+// cannot read enough random data.
+func Random() *[Size]byte {
+	var r [UniformSize]byte
+	randombytes.MustRead(r[:])
+	return FromUniform(&r)
+}
+
+// ScalarMult returns n*p, after clamping n the way X25519 and Ed25519 do: the
+// three least significant bits are cleared, the most significant bit is
+// cleared and the second most significant bit is set. It returns an error if
+// p is not a valid point as defined by IsValidPoint, if n is zero, or if the
+// result is the identity.
+func ScalarMult(n, p *[ScalarSize]byte) (*[Size]byte, error) {
+	return scalarMult(n, p, true)
+}
+
+// ScalarMultNoClamp is like ScalarMult, but does not clamp n; only its most
+// significant bit is cleared.
+func ScalarMultNoClamp(n, p *[ScalarSize]byte) (*[Size]byte, error) {
+	return scalarMult(n, p, false)
+}
+
+// ScalarMultBase returns n*B, where B is the edwards25519 base point, after
+// clamping n as described for ScalarMult. It returns an error if n is zero,
+// or if the result is the identity.
+func ScalarMultBase(n *[ScalarSize]byte) (*[Size]byte, error) {
+	return scalarMultBase(n, true)
+}
+
+// ScalarMultBaseNoClamp is like ScalarMultBase, but does not clamp n; only
+// its most significant bit is cleared.
+func ScalarMultBaseNoClamp(n *[ScalarSize]byte) (*[Size]byte, error) {
+	return scalarMultBase(n, false)
+}
+
+// multScalar prepares n for multiplication. The result is reduced modulo L,
+// which gives the same product as libsodium's unreduced multiplication
+// because the points involved are all in the prime-order subgroup.
+func multScalar(n *[ScalarSize]byte, clamp bool) *edwards.Scalar {
+	t := *n
+	if clamp {
+		t[0] &= 248
+		t[31] |= 64
+	}
+	t[31] &= 127
+	return scalar.New(&t)
+}
+
+func scalarMult(n, p *[ScalarSize]byte, clamp bool) (*[Size]byte, error) {
+	pp, err := decodeChecked(p)
+	if err != nil {
+		return nil, err
+	}
+	return checkResult(n, pp.ScalarMult(multScalar(n, clamp), pp))
+}
+
+func scalarMultBase(n *[ScalarSize]byte, clamp bool) (*[Size]byte, error) {
+	return checkResult(n, new(edwards.Point).ScalarBaseMult(multScalar(n, clamp)))
+}
+
+func checkResult(n *[ScalarSize]byte, q *edwards.Point) (*[Size]byte, error) {
+	if *n == [ScalarSize]byte{} || q.Equal(identity) == 1 {
+		return nil, errIdentity
+	}
+	return encode(q), nil
+}
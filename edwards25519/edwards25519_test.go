@@ -0,0 +1,383 @@
+package edwards25519
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodePoint(t *testing.T, s string) *[Size]byte {
+	t.Helper()
+	p := new([Size]byte)
+	copy(p[:], decodeHex(t, s))
+	return p
+}
+
+// Test vectors were generated with libsodium 1.0.18.
+
+const basePoint = "5866666666666666666666666666666666666666666666666666666666666666"
+
+var fromUniformTests = []struct {
+	in, out string
+}{
+	{
+		"000b16212c37424d58636e79848f9aa5b0bbc6d1dce7f2fd08131e29343f4a55",
+		"47cc9d1a3d8cf3ad77c8d935f07cee81653b7f91223ffef660b586b3f436ab9d",
+	},
+	{
+		"25303b46515c67727d88939ea9b4bfcad5e0ebf6010c17222d38434e59646f7a",
+		"3cb1c4ee0d0f4e2fb051eb8d08b0e22b7c03cb4ed9719d7cff54ea58e69a75c1",
+	},
+	{
+		"4a55606b76818c97a2adb8c3ced9e4effa05101b26313c47525d68737e89949f",
+		"8922cb6b2946a6cf1e1dc8f2a7d6179e7a1b255b4ef3f079ad4aff1726decf30",
+	},
+	{
+		"6f7a85909ba6b1bcc7d2dde8f3fe09141f2a35404b56616c77828d98a3aeb9c4",
+		"79316314b410dff1b80bae9bee63b05e769e87a48d65a3777caf5402761643c9",
+	},
+}
+
+var fromHashTests = []struct {
+	in, out string
+}{
+	{
+		"00070e151c232a31383f464d545b626970777e858c939aa1a8afb6bdc4cbd2d9e0e7eef5fc030a11181f262d343b424950575e656c737a81888f969da4abb2b9",
+		"f9954499785ab2d5c6c2bff8ff190bc0f6915962887aad76013f74d358c5db2a",
+	},
+	{
+		"1f262d343b424950575e656c737a81888f969da4abb2b9c0c7ced5dce3eaf1f8ff060d141b222930373e454c535a61686f767d848b9299a0a7aeb5bcc3cad1d8",
+		"355b091e8b4b666763ba14a0f77f90b960d2a4e64c5f7bf15cd3772ecc5550a3",
+	},
+	{
+		"3e454c535a61686f767d848b9299a0a7aeb5bcc3cad1d8dfe6edf4fb020910171e252c333a41484f565d646b727980878e959ca3aab1b8bfc6cdd4dbe2e9f0f7",
+		"abc91c9d685452912c3c780598d5de3817f67415c3f767d62aaec17d162510bc",
+	},
+	{
+		"5d646b727980878e959ca3aab1b8bfc6cdd4dbe2e9f0f7fe050c131a21282f363d444b525960676e757c838a91989fa6adb4bbc2c9d0d7dee5ecf3fa01080f16",
+		"e8c8a38b6982a4a3a0cd072aae821fb67c818ba8a40e16139e099c8e01956d42",
+	},
+}
+
+func TestFromUniform(t *testing.T) {
+	for i, tt := range fromUniformTests {
+		var r [UniformSize]byte
+		copy(r[:], decodeHex(t, tt.in))
+		p := FromUniform(&r)
+		if got := hex.EncodeToString(p[:]); got != tt.out {
+			t.Errorf("%d: got %s, want %s", i, got, tt.out)
+		}
+		if !IsValidPoint(p) {
+			t.Errorf("%d: FromUniform returned an invalid point", i)
+		}
+	}
+}
+
+func TestFromHash(t *testing.T) {
+	for i, tt := range fromHashTests {
+		var h [HashSize]byte
+		copy(h[:], decodeHex(t, tt.in))
+		p := FromHash(&h)
+		if got := hex.EncodeToString(p[:]); got != tt.out {
+			t.Errorf("%d: got %s, want %s", i, got, tt.out)
+		}
+		if !IsValidPoint(p) {
+			t.Errorf("%d: FromHash returned an invalid point", i)
+		}
+	}
+}
+
+var addTests = []struct {
+	name            string
+	p               string
+	valid           bool
+	sum, difference string // with the base point; empty if p is rejected
+}{
+	{
+		"the identity",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		false,
+		"5866666666666666666666666666666666666666666666666666666666666666",
+		"58666666666666666666666666666666666666666666666666666666666666e6",
+	},
+	{
+		"the identity, with the sign bit set",
+		"0100000000000000000000000000000000000000000000000000000000000080",
+		false,
+		"5866666666666666666666666666666666666666666666666666666666666666",
+		"58666666666666666666666666666666666666666666666666666666666666e6",
+	},
+	{
+		"a point of order 8",
+		"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+		false,
+		"da99e28ba529cdde35a25fba9059e78ecaee239f99755b9b1aa4f65df00803e2",
+		"55ae61520ca466adcc4ae4a32dc1633a5d749c64a5b50f136fc3469f27e48766",
+	},
+	{
+		"a non-canonical point of order 4",
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		false,
+		"5252cc0a7f208133b620acbd4537eba2a4123bf0a8c2e4f980c3b31bb69765ea",
+		"9bad33f580df7ecc49df5342bac8145d5bedc40f573d1b067f3c4ce449689a95",
+	},
+	{
+		"a non-canonical encoding of the identity",
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		false,
+		"5866666666666666666666666666666666666666666666666666666666666666",
+		"58666666666666666666666666666666666666666666666666666666666666e6",
+	},
+	{
+		"a point outside the prime-order subgroup",
+		"da99e28ba529cdde35a25fba9059e78ecaee239f99755b9b1aa4f65df00803e2",
+		false,
+		"543ac908ca97124ab06caab11552257e53aba22a4e66b769175823a19a0452e0",
+		"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+	},
+	{
+		"not on the curve",
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		false,
+		"",
+		"",
+	},
+	{
+		"the base point",
+		"5866666666666666666666666666666666666666666666666666666666666666",
+		true,
+		"c9a3f86aae465f0e56513864510f3997561fa2c9e85ea21dc2292309f3cd6022",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+	},
+}
+
+func TestAddSub(t *testing.T) {
+	base := decodePoint(t, basePoint)
+	for _, tt := range addTests {
+		p := decodePoint(t, tt.p)
+		if got := IsValidPoint(p); got != tt.valid {
+			t.Errorf("%s: IsValidPoint: got %t, want %t", tt.name, got, tt.valid)
+		}
+		sum, err := Add(p, base)
+		if tt.sum == "" {
+			if err == nil {
+				t.Errorf("%s: Add: expected error, got %x", tt.name, sum)
+			}
+		} else if err != nil {
+			t.Errorf("%s: Add: %v", tt.name, err)
+		} else if got := hex.EncodeToString(sum[:]); got != tt.sum {
+			t.Errorf("%s: Add: got %s, want %s", tt.name, got, tt.sum)
+		}
+		difference, err := Sub(p, base)
+		if tt.difference == "" {
+			if err == nil {
+				t.Errorf("%s: Sub: expected error, got %x", tt.name, difference)
+			}
+		} else if err != nil {
+			t.Errorf("%s: Sub: %v", tt.name, err)
+		} else if got := hex.EncodeToString(difference[:]); got != tt.difference {
+			t.Errorf("%s: Sub: got %s, want %s", tt.name, got, tt.difference)
+		}
+	}
+}
+
+var scalarMultTests = []struct {
+	n                                    string
+	mult, multNoClamp, base, baseNoClamp string // empty for an error
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"",
+		"",
+		"",
+		"",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"d1cc321d5a2aa0a6190b3be613971689a162324d40491bc51e38870fd5104b8f",
+		"47cc9d1a3d8cf3ad77c8d935f07cee81653b7f91223ffef660b586b3f436ab9d",
+		"693e47972caf527c7883ad1b39822f026f47db2ab0e1919955b8993aa04411d1",
+		"5866666666666666666666666666666666666666666666666666666666666666",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"98159fcc25e193515213f87f97a8b63b27334a6f191714c92c7a833cf547f140",
+		"bc9c274c61cc8c07281277ff699df87b6dfa207f6215beefb4fee593f3a9dfbd",
+		"12e9a68b73fd5aacdbcaf3e88c46fea6ebedb1aa84eed1842f07f8edab65e3a7",
+		"af9b6a948d400c38197f1d0675ec3d6630780c67ad4dfc7e5fe6e6a39cc90fd3",
+	},
+	{
+		"05121f2c394653606d7a8794a1aebbc8d5e2effc091623303d4a5764717e8b98",
+		"1cea2833ee2654c334df46c8bd088bc3e896d97259010d293c726b9d653c4184",
+		"0a0890d7e8b443c169d5f72397355195c84792becf46a1db32943ba182df97c9",
+		"57b8da6da4cdf659f1c79fc0ef23c3d74260c3cbd483e05d551b8e1701b46775",
+		"3702a20457179939f7c5386c3e303036ce6c82ab6e27b9c137daec37711520c2",
+	},
+	{
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ed52ca1a5d5ed51f9a22ed394f40218a560ef134336cf810df8cdc7aa201a2ee",
+		"",
+		"973eed452a012e957b8a9e22c1bbaa991c90d73514d8072cf85d952f5d64c6d2",
+		"",
+	},
+	{
+		"0800000000000000000000000000000000000000000000000000000000000000",
+		"04d575c49acdd832df8bfdc37f725ce4e043f189210a948a0ec8df9ee9cff4d0",
+		"27e38e23274da62bfcf14c0007c559213d83bb22493d7d3ebf112d68befdd1d2",
+		"c9877dfd1ccda6393a15aed8aba06798456798355f2a9da4e182fecd40290157",
+		"b4b937fca95b2f1e93e41e62fc3c78818ff38a66096fad6e7973e5c90006d321",
+	},
+}
+
+func TestScalarMult(t *testing.T) {
+	p := decodePoint(t, "47cc9d1a3d8cf3ad77c8d935f07cee81653b7f91223ffef660b586b3f436ab9d")
+	check := func(name string, i int, q *[Size]byte, err error, want string) {
+		t.Helper()
+		if want == "" {
+			if err == nil {
+				t.Errorf("%d: %s: expected error, got %x", i, name, q)
+			}
+			return
+		}
+		if err != nil {
+			t.Errorf("%d: %s: %v", i, name, err)
+			return
+		}
+		if got := hex.EncodeToString(q[:]); got != want {
+			t.Errorf("%d: %s: got %s, want %s", i, name, got, want)
+		}
+	}
+	for i, tt := range scalarMultTests {
+		var n [ScalarSize]byte
+		copy(n[:], decodeHex(t, tt.n))
+		q, err := ScalarMult(&n, p)
+		check("ScalarMult", i, q, err, tt.mult)
+		q, err = ScalarMultNoClamp(&n, p)
+		check("ScalarMultNoClamp", i, q, err, tt.multNoClamp)
+		q, err = ScalarMultBase(&n)
+		check("ScalarMultBase", i, q, err, tt.base)
+		q, err = ScalarMultBaseNoClamp(&n)
+		check("ScalarMultBaseNoClamp", i, q, err, tt.baseNoClamp)
+	}
+
+	n := [ScalarSize]byte{0: 1}
+	for _, tt := range addTests {
+		if tt.valid {
+			continue
+		}
+		if q, err := ScalarMult(&n, decodePoint(t, tt.p)); err == nil {
+			t.Errorf("ScalarMult accepted %s: got %x", tt.name, q)
+		}
+	}
+}
+
+var scalarTests = []struct {
+	s, negation, complement, inverse string
+}{
+	{
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+	},
+	{
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"a4ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"522f982d76cbdee2ce448f81bf5c54b0c48c4863b4759cfef0aa6e8028e9770e",
+	},
+	// L itself. libsodium's crypto_core_ed25519_scalar_invert returns zero
+	// for it, and only fails on the all-zero string.
+	{
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"",
+	},
+}
+
+func TestScalar(t *testing.T) {
+	for i, tt := range scalarTests {
+		var s [ScalarSize]byte
+		copy(s[:], decodeHex(t, tt.s))
+		if got := hex.EncodeToString(ScalarNegate(&s)[:]); got != tt.negation {
+			t.Errorf("%d: ScalarNegate: got %s, want %s", i, got, tt.negation)
+		}
+		if got := hex.EncodeToString(ScalarComplement(&s)[:]); got != tt.complement {
+			t.Errorf("%d: ScalarComplement: got %s, want %s", i, got, tt.complement)
+		}
+		inverse, err := ScalarInvert(&s)
+		if tt.inverse == "" {
+			if err == nil {
+				t.Errorf("%d: ScalarInvert: expected error, got %x", i, inverse)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: ScalarInvert: %v", i, err)
+		}
+		if got := hex.EncodeToString(inverse[:]); got != tt.inverse {
+			t.Errorf("%d: ScalarInvert: got %s, want %s", i, got, tt.inverse)
+		}
+	}
+
+	var x, y [ScalarSize]byte
+	copy(x[:], decodeHex(t, "ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010"))
+	copy(y[:], decodeHex(t, "4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e"))
+	if got, want := hex.EncodeToString(ScalarAdd(&x, &y)[:]), "49d40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e"; got != want {
+		t.Errorf("ScalarAdd: got %s, want %s", got, want)
+	}
+	if got, want := hex.EncodeToString(ScalarSub(&x, &y)[:]), "a2ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01"; got != want {
+		t.Errorf("ScalarSub: got %s, want %s", got, want)
+	}
+	if got, want := hex.EncodeToString(ScalarMul(&x, &y)[:]), "a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01"; got != want {
+		t.Errorf("ScalarMul: got %s, want %s", got, want)
+	}
+	var wide [NonReducedScalarSize]byte
+	copy(wide[:], decodeHex(t, "00070e151c232a31383f464d545b626970777e858c939aa1a8afb6bdc4cbd2d9e0e7eef5fc030a11181f262d343b424950575e656c737a81888f969da4abb2b9"))
+	if got, want := hex.EncodeToString(ScalarReduce(&wide)[:]), "4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e"; got != want {
+		t.Errorf("ScalarReduce: got %s, want %s", got, want)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	p, q := Random(), Random()
+	if !IsValidPoint(p) || !IsValidPoint(q) {
+		t.Fatalf("Random returned an invalid point")
+	}
+	if *p == *q {
+		t.Errorf("Random returned the same point twice")
+	}
+
+	// n*(r*B) == (n*r)*B
+	n, r := ScalarRandom(), ScalarRandom()
+	rB, err := ScalarMultBaseNoClamp(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q1, err := ScalarMultNoClamp(n, rB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q2, err := ScalarMultBaseNoClamp(ScalarMul(n, r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *q1 != *q2 {
+		t.Errorf("n*(r*B) != (n*r)*B")
+	}
+}
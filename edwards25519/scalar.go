@@ -0,0 +1,63 @@
+package edwards25519
+
+import (
+	"errors"
+
+	"github.com/kevinburke/nacl/internal/scalar"
+)
+
+// Scalars are integers modulo the order of the prime-order subgroup,
+// L = 2^252 + 27742317777372353535851937790883648493. The functions below
+// return scalars in canonical, fully reduced form, and accept any 32-byte
+// value, reducing it first.
+
+var errZeroScalar = errors.New("edwards25519: scalar is zero")
+
+// ScalarRandom returns a uniformly distributed random non-zero scalar. It
+// panics if it cannot read enough random data.
+func ScalarRandom() *[ScalarSize]byte {
+	return scalar.Random()
+}
+
+// ScalarReduce reduces a 64-byte little-endian integer modulo L. If s is
+// uniformly distributed, so is the result.
+func ScalarReduce(s *[NonReducedScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Reduce(s)
+}
+
+// ScalarInvert returns the multiplicative inverse of s modulo L. It returns
+// an error if s is all zeros. As in libsodium, a nonzero s that is a multiple
+// of L, such as L itself, has no inverse either, but ScalarInvert returns the
+// zero scalar for it rather than an error.
+func ScalarInvert(s *[ScalarSize]byte) (*[ScalarSize]byte, error) {
+	inverse, ok := scalar.Invert(s)
+	if !ok {
+		return nil, errZeroScalar
+	}
+	return inverse, nil
+}
+
+// ScalarNegate returns -s modulo L.
+func ScalarNegate(s *[ScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Negate(s)
+}
+
+// ScalarComplement returns 1 - s modulo L.
+func ScalarComplement(s *[ScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Complement(s)
+}
+
+// ScalarAdd returns x + y modulo L.
+func ScalarAdd(x, y *[ScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Add(x, y)
+}
+
+// ScalarSub returns x - y modulo L.
+func ScalarSub(x, y *[ScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Sub(x, y)
+}
+
+// ScalarMul returns x * y modulo L.
+func ScalarMul(x, y *[ScalarSize]byte) *[ScalarSize]byte {
+	return scalar.Mul(x, y)
+}
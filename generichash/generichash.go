@@ -0,0 +1,104 @@
+/*
+Package generichash computes BLAKE2b hashes of arbitrary length messages,
+optionally keyed.
+
+BLAKE2b is faster than SHA-512 and, unlike SHA-2, keying it turns it directly
+into a MAC, without needing HMAC. The output can be anywhere between SizeMin
+and SizeMax bytes long; Size is a good default. A key, if used, must be
+between KeySizeMin and KeySizeMax bytes long.
+
+SumSaltPersonal and NewSaltPersonal additionally take a salt and a
+personalization string, which can be used to derive unrelated hashes from the
+same key and message, for example for different purposes within one protocol.
+
+This package is interoperable with libsodium's crypto_generichash and
+crypto_generichash_blake2b_salt_personal:
+https://doc.libsodium.org/hashing/generic_hashing.
+*/
+package generichash // import "github.com/kevinburke/nacl/generichash"
+
+import (
+	"errors"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// Size is the recommended output size, in bytes.
+	Size = 32
+	// SizeMin is the smallest supported output size, in bytes.
+	SizeMin = 16
+	// SizeMax is the largest supported output size, in bytes.
+	SizeMax = 64
+
+	// KeySize is the recommended key size, in bytes.
+	KeySize = 32
+	// KeySizeMin is the smallest supported key size, in bytes, other than
+	// zero, which means no key.
+	KeySizeMin = 16
+	// KeySizeMax is the largest supported key size, in bytes.
+	KeySizeMax = 64
+
+	// SaltSize is the size, in bytes, of a salt.
+	SaltSize = 16
+	// PersonalSize is the size, in bytes, of a personalization string.
+	PersonalSize = 16
+)
+
+var (
+	errSize    = errors.New("generichash: invalid output size")
+	errKeySize = errors.New("generichash: invalid key size")
+)
+
+func checkSizes(size int, key []byte) error {
+	if size < SizeMin || size > SizeMax {
+		return errSize
+	}
+	if len(key) != 0 && (len(key) < KeySizeMin || len(key) > KeySizeMax) {
+		return errKeySize
+	}
+	return nil
+}
+
+// Sum returns the size-byte BLAKE2b hash of message. If key is not empty, the
+// hash is keyed, and can be used as a MAC. An error is returned if size or the
+// length of key is out of range.
+func Sum(size int, message, key []byte) ([]byte, error) {
+	h, err := New(size, key)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(message)
+	return h.Sum(nil), nil
+}
+
+// New returns a hash.Hash computing the size-byte BLAKE2b hash of everything
+// written to it, keyed with key if it is not empty. An error is returned if
+// size or the length of key is out of range.
+func New(size int, key []byte) (hash.Hash, error) {
+	if err := checkSizes(size, key); err != nil {
+		return nil, err
+	}
+	return blake2b.New(size, key)
+}
+
+// SumSaltPersonal is like Sum, but also takes a salt and a personalization
+// string. A nil salt or personal is treated as all zeros.
+func SumSaltPersonal(size int, message, key []byte, salt *[SaltSize]byte, personal *[PersonalSize]byte) ([]byte, error) {
+	h, err := NewSaltPersonal(size, key, salt, personal)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(message)
+	return h.Sum(nil), nil
+}
+
+// NewSaltPersonal is like New, but also takes a salt and a personalization
+// string. A nil salt or personal is treated as all zeros.
+func NewSaltPersonal(size int, key []byte, salt *[SaltSize]byte, personal *[PersonalSize]byte) (hash.Hash, error) {
+	if err := checkSizes(size, key); err != nil {
+		return nil, err
+	}
+	return newDigest(size, key, salt, personal), nil
+}
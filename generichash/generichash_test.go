@@ -0,0 +1,157 @@
+package generichash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func testBytes(n int, mul, add byte) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)*mul + add
+	}
+	return b
+}
+
+func testMessage(n int) []byte { return testBytes(n, 7, 3) }
+func testKey(n int) []byte     { return testBytes(n, 5, 1) }
+
+// Test vectors were generated with libsodium 1.0.18's crypto_generichash.
+var sumTests = []struct {
+	size, messageLen, keyLen int
+	out                      string
+}{
+	{32, 0, 0, "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"},
+	{16, 3, 0, "09e03a807474593d5cb98909e5d502cd"},
+	{64, 128, 0, "2d9e329f42afa3601d646692b81c13e87fcaff5bf15972e9813d7373cb6d181f9599f4d513d4af4fd6ebd37497aceb29aba5ee23ed764d8510b552bd088814fb"},
+	{32, 129, 32, "d996c5c174847f90119517e7c9f23aa291d155463f72d57a293f71a80a320be1"},
+	{64, 300, 64, "7135b2a8802be827837af908a3f366a52079d21fc7e048c4984aac6fa397501536a234f2b179c780b550cb0facd22c5ead97feae31c0ffa3a4edea53bb9033eb"},
+	{16, 1000, 16, "e5b4b9c05d724f1e9aef56954e8d7f7a"},
+}
+
+// writeInPieces writes message to h in pieces of varying sizes, to exercise
+// the buffering in Write.
+func writeInPieces(h hash.Hash, message []byte) {
+	for n := 1; len(message) > 0; n = n*3 + 1 {
+		if n > len(message) {
+			n = len(message)
+		}
+		h.Write(message[:n])
+		message = message[n:]
+	}
+}
+
+func TestSum(t *testing.T) {
+	for _, tt := range sumTests {
+		message, key := testMessage(tt.messageLen), testKey(tt.keyLen)
+		out, err := Sum(tt.size, message, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != tt.out {
+			t.Errorf("Sum(%d, %d, %d): got %s, want %s", tt.size, tt.messageLen, tt.keyLen, got, tt.out)
+		}
+
+		h, err := New(tt.size, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeInPieces(h, message)
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.out {
+			t.Errorf("New(%d, %d, %d): got %s, want %s", tt.size, tt.messageLen, tt.keyLen, got, tt.out)
+		}
+
+		// With no salt or personalization string, the generic implementation
+		// must match.
+		out, err = SumSaltPersonal(tt.size, message, key, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != tt.out {
+			t.Errorf("SumSaltPersonal(%d, %d, %d): got %s, want %s", tt.size, tt.messageLen, tt.keyLen, got, tt.out)
+		}
+	}
+}
+
+var salt = [SaltSize]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+var personal = [PersonalSize]byte{'n', 'a', 'c', 'l', '-', 'p', 'e', 'r', 's', 'o', 'n', 'a', 'l', 'i', 'z', '!'}
+
+// Generated with libsodium 1.0.18's crypto_generichash_blake2b_salt_personal.
+var saltPersonalTests = []struct {
+	size, messageLen, keyLen int
+	salt, personal           bool
+	out                      string
+}{
+	{32, 0, 0, true, true, "f39aa05f1082ec0e696b417fb3a4c1962d7b8744d525ea7520e31bfbe0b2b886"},
+	{64, 200, 32, true, false, "d7d2de45ac31382f0299fc007c883e99ab9b516686db1a321a27bf8b8f32d33790d3443b72a736d33841d7a8d87af5146458497668b400fe0d78c1725e862456"},
+	{32, 129, 0, false, true, "b058b0e1eb169be60d93199274163f818e2b39455a596bcaf71c53714c4c70ae"},
+	{16, 5, 64, true, true, "78784ea8c6ccc844f676dd7d3a62788d"},
+}
+
+func TestSumSaltPersonal(t *testing.T) {
+	for i, tt := range saltPersonalTests {
+		message, key := testMessage(tt.messageLen), testKey(tt.keyLen)
+		var s *[SaltSize]byte
+		var p *[PersonalSize]byte
+		if tt.salt {
+			s = &salt
+		}
+		if tt.personal {
+			p = &personal
+		}
+		out, err := SumSaltPersonal(tt.size, message, key, s, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != tt.out {
+			t.Errorf("%d: SumSaltPersonal: got %s, want %s", i, got, tt.out)
+		}
+
+		h, err := NewSaltPersonal(tt.size, key, s, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeInPieces(h, message)
+		sum := h.Sum(nil)
+		if got := hex.EncodeToString(sum); got != tt.out {
+			t.Errorf("%d: NewSaltPersonal: got %s, want %s", i, got, tt.out)
+		}
+		// Sum must not change the state, and Reset must restore it.
+		if !bytes.Equal(h.Sum(nil), sum) {
+			t.Errorf("%d: calling Sum twice gave different results", i)
+		}
+		h.Reset()
+		h.Write(message)
+		if !bytes.Equal(h.Sum(nil), sum) {
+			t.Errorf("%d: wrong result after Reset", i)
+		}
+	}
+}
+
+func TestSizes(t *testing.T) {
+	for _, size := range []int{0, SizeMin - 1, SizeMax + 1} {
+		if _, err := Sum(size, nil, nil); err == nil {
+			t.Errorf("Sum accepted output size %d", size)
+		}
+		if _, err := NewSaltPersonal(size, nil, nil, nil); err == nil {
+			t.Errorf("NewSaltPersonal accepted output size %d", size)
+		}
+	}
+	for _, keyLen := range []int{1, KeySizeMin - 1, KeySizeMax + 1} {
+		if _, err := New(Size, make([]byte, keyLen)); err == nil {
+			t.Errorf("New accepted key size %d", keyLen)
+		}
+		if _, err := SumSaltPersonal(Size, nil, make([]byte, keyLen), nil, nil); err == nil {
+			t.Errorf("SumSaltPersonal accepted key size %d", keyLen)
+		}
+	}
+	h, err := New(SizeMin, make([]byte, KeySizeMax))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Size() != SizeMin || h.BlockSize() != 128 {
+		t.Errorf("got Size %d, BlockSize %d", h.Size(), h.BlockSize())
+	}
+}
@@ -0,0 +1,90 @@
+// Package salsa implements the Salsa20 stream cipher with an 8-byte nonce
+// and a configurable number of rounds, shared by the stream/salsa20,
+// stream/salsa2012 and stream/salsa208 packages.
+// golang.org/x/crypto/salsa20/salsa only provides the full 20 round version,
+// which is used for 20 rounds since it has an assembly implementation.
+package salsa // import "github.com/kevinburke/nacl/internal/salsa"
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"golang.org/x/crypto/salsa20/salsa"
+)
+
+const (
+	// NonceSize is the size, in bytes, of a Salsa20 nonce.
+	NonceSize = 8
+	// BlockSize is the size, in bytes, of a keystream block.
+	BlockSize = 64
+)
+
+// sigma is the Salsa20 constant "expand 32-byte k".
+var sigma = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// core applies the Salsa20 core function with the given number of rounds to
+// the 16-byte input in (the nonce, then the block counter) and key, and puts
+// the result into out.
+func core(out *[64]byte, in *[16]byte, key *[32]byte, rounds int) {
+	var j [16]uint32
+	j[0], j[5], j[10], j[15] = sigma[0], sigma[1], sigma[2], sigma[3]
+	for i := range 4 {
+		j[1+i] = binary.LittleEndian.Uint32(key[4*i:])
+		j[11+i] = binary.LittleEndian.Uint32(key[16+4*i:])
+		j[6+i] = binary.LittleEndian.Uint32(in[4*i:])
+	}
+
+	x := j
+	quarterRound := func(a, b, c, d int) {
+		x[b] ^= bits.RotateLeft32(x[a]+x[d], 7)
+		x[c] ^= bits.RotateLeft32(x[b]+x[a], 9)
+		x[d] ^= bits.RotateLeft32(x[c]+x[b], 13)
+		x[a] ^= bits.RotateLeft32(x[d]+x[c], 18)
+	}
+	for i := 0; i < rounds; i += 2 {
+		// Column round.
+		quarterRound(0, 4, 8, 12)
+		quarterRound(5, 9, 13, 1)
+		quarterRound(10, 14, 2, 6)
+		quarterRound(15, 3, 7, 11)
+		// Row round.
+		quarterRound(0, 1, 2, 3)
+		quarterRound(5, 6, 7, 4)
+		quarterRound(10, 11, 8, 9)
+		quarterRound(15, 12, 13, 14)
+	}
+	for i := range x {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i]+j[i])
+	}
+}
+
+// XORKeyStream XORs in with the Salsa20 keystream for the given number of
+// rounds, nonce and key, starting at block number counter, and writes the
+// result to out, which must be at least as long as in. in and out must
+// overlap entirely or not at all. The 64-bit block counter wraps around.
+func XORKeyStream(out, in []byte, nonce *[NonceSize]byte, counter uint64, key *[32]byte, rounds int) {
+	var c [16]byte
+	copy(c[:], nonce[:])
+	binary.LittleEndian.PutUint64(c[8:], counter)
+	if rounds == 20 {
+		salsa.XORKeyStream(out, in, &c, key)
+		return
+	}
+	genericXORKeyStream(out, in, &c, key, rounds)
+}
+
+// genericXORKeyStream is XORKeyStream for any number of rounds. counter holds
+// the nonce followed by the block counter; it is not modified.
+func genericXORKeyStream(out, in []byte, counter *[16]byte, key *[32]byte, rounds int) {
+	var block [BlockSize]byte
+	c := *counter
+	for len(in) > 0 {
+		core(&block, &c, key, rounds)
+		n := min(len(in), len(block))
+		for i := range n {
+			out[i] = in[i] ^ block[i]
+		}
+		in, out = in[n:], out[n:]
+		binary.LittleEndian.PutUint64(c[8:], binary.LittleEndian.Uint64(c[8:])+1)
+	}
+}
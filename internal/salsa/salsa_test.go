@@ -0,0 +1,40 @@
+package salsa
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/salsa20/salsa"
+)
+
+func TestSalsa20(t *testing.T) {
+	// With 20 rounds, genericXORKeyStream must match the full Salsa20 in
+	// golang.org/x/crypto, including when the block counter carries.
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	in := make([]byte, 1000)
+	for i := range in {
+		in[i] = byte(i)
+	}
+	for _, counter := range []*[16]byte{
+		{},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		want := make([]byte, len(in))
+		salsa.XORKeyStream(want, in, counter, &key)
+		for _, n := range []int{0, 1, 64, 65, 1000} {
+			got := make([]byte, n)
+			saved := *counter
+			genericXORKeyStream(got, in[:n], counter, &key, 20)
+			if !bytes.Equal(got, want[:n]) {
+				t.Errorf("counter %x, length %d: got %x, want %x", counter, n, got, want[:n])
+			}
+			if *counter != saved {
+				t.Errorf("genericXORKeyStream modified counter")
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package scalar implements arithmetic on scalars modulo the order of the
+// edwards25519 prime-order subgroup,
+// L = 2^252 + 27742317777372353535851937790883648493, using libsodium's
+// byte-array conventions. It is shared by the edwards25519 and ristretto255
+// packages.
+//
+// Every function accepts any 32-byte little-endian value, reducing it first,
+// and returns a canonical, fully reduced scalar.
+package scalar // import "github.com/kevinburke/nacl/internal/scalar"
+
+import (
+	"crypto/subtle"
+
+	"filippo.io/edwards25519"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+const (
+	// Size is the size, in bytes, of a scalar.
+	Size = 32
+	// WideSize is the size, in bytes, of the input to Reduce.
+	WideSize = 64
+)
+
+// NewWide reduces a 64-byte little-endian integer modulo L.
+func NewWide(b *[WideSize]byte) *edwards25519.Scalar {
+	s, err := edwards25519.NewScalar().SetUniformBytes(b[:])
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// New reduces a 32-byte little-endian integer modulo L.
+func New(b *[Size]byte) *edwards25519.Scalar {
+	var wide [WideSize]byte
+	copy(wide[:], b[:])
+	return NewWide(&wide)
+}
+
+// Bytes returns the canonical encoding of s.
+func Bytes(s *edwards25519.Scalar) *[Size]byte {
+	out := new([Size]byte)
+	copy(out[:], s.Bytes())
+	return out
+}
+
+// Random returns a uniformly distributed random non-zero scalar. It panics if
+// it cannot read enough random data.
+func Random() *[Size]byte {
+	var b [WideSize]byte
+	for {
+		randombytes.MustRead(b[:])
+		s := NewWide(&b)
+		if s.Equal(edwards25519.NewScalar()) == 0 {
+			return Bytes(s)
+		}
+	}
+}
+
+// Reduce returns s modulo L.
+func Reduce(s *[WideSize]byte) *[Size]byte {
+	return Bytes(NewWide(s))
+}
+
+// Invert returns the multiplicative inverse of s modulo L, or false if s is
+// all zeros. Like libsodium, it returns zero rather than false for a nonzero
+// s that is a multiple of L.
+func Invert(s *[Size]byte) (*[Size]byte, bool) {
+	var zero [Size]byte
+	if subtle.ConstantTimeCompare(s[:], zero[:]) == 1 {
+		return nil, false
+	}
+	x := New(s)
+	return Bytes(x.Invert(x)), true
+}
+
+// Negate returns -s modulo L.
+func Negate(s *[Size]byte) *[Size]byte {
+	x := New(s)
+	return Bytes(x.Negate(x))
+}
+
+// Complement returns 1 - s modulo L.
+func Complement(s *[Size]byte) *[Size]byte {
+	one := [Size]byte{0: 1}
+	return Sub(&one, s)
+}
+
+// Add returns x + y modulo L.
+func Add(x, y *[Size]byte) *[Size]byte {
+	s := New(x)
+	return Bytes(s.Add(s, New(y)))
+}
+
+// Sub returns x - y modulo L.
+func Sub(x, y *[Size]byte) *[Size]byte {
+	s := New(x)
+	return Bytes(s.Subtract(s, New(y)))
+}
+
+// Mul returns x * y modulo L.
+func Mul(x, y *[Size]byte) *[Size]byte {
+	s := New(x)
+	return Bytes(s.Multiply(s, New(y)))
+}
@@ -0,0 +1,33 @@
+package kdf_test
+
+import (
+	"fmt"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/kdf"
+	"github.com/kevinburke/nacl/secretbox"
+)
+
+func Example() {
+	// Load the master key from a safe place. (Obviously don't use this
+	// example key for anything real.)
+	masterKey, err := nacl.Load("6368616e676520746869732070617373776f726420746f206120736563726574")
+	if err != nil {
+		panic(err)
+	}
+
+	// Derive a different key for each tenant, so that a key leaked by one
+	// tenant can't be used to read another tenant's data.
+	context := [kdf.ContextSize]byte{'t', 'e', 'n', 'a', 'n', 't', 's', '!'}
+	tenantKey := kdf.DeriveKey(1234, &context, masterKey)
+	fmt.Printf("%x\n", *tenantKey)
+
+	encrypted := secretbox.EasySeal([]byte("hello world"), tenantKey)
+	decrypted, err := secretbox.EasyOpen(encrypted, tenantKey)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(decrypted))
+	// Output: b09e44200ed48127b9a4c2e842a346ecd46d0436105ac2e1d980c55aed0ac8ca
+	// hello world
+}
@@ -0,0 +1,135 @@
+/*
+Package kdf derives subkeys from a single master key.
+
+DeriveKey and DeriveFromKey derive any number of subkeys from a master
+nacl.Key, each identified by a 64-bit subkey ID and an 8-byte context. The
+context describes what the subkeys are for, for example "UserName" or
+"__auth__", so that subkeys for different purposes can't be confused even
+if they share an ID. The master key can't be recovered from a subkey, and
+knowing one subkey reveals nothing about the others. Subkeys are computed
+with keyed BLAKE2b, and match libsodium's crypto_kdf_derive_from_key:
+https://doc.libsodium.org/key_derivation.
+
+The HKDF functions implement HKDF (RFC 5869) with SHA-256 or SHA-512,
+compatible with libsodium's crypto_kdf_hkdf_sha256 and crypto_kdf_hkdf_sha512.
+HKDF can derive keys from input keying material that is not uniformly random,
+such as a Diffie-Hellman shared secret, and can produce longer outputs.
+*/
+package kdf // import "github.com/kevinburke/nacl/kdf"
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/generichash"
+)
+
+const (
+	// ContextSize is the size, in bytes, of a context.
+	ContextSize = 8
+	// SizeMin is the smallest subkey size, in bytes, that DeriveFromKey
+	// accepts.
+	SizeMin = 16
+	// SizeMax is the largest subkey size, in bytes, that DeriveFromKey
+	// accepts.
+	SizeMax = 64
+
+	// HKDFSHA256Size is the size, in bytes, of an HKDF-SHA-256 pseudorandom
+	// key.
+	HKDFSHA256Size = sha256.Size
+	// HKDFSHA512Size is the size, in bytes, of an HKDF-SHA-512 pseudorandom
+	// key.
+	HKDFSHA512Size = sha512.Size
+	// HKDFSHA256SizeMax is the largest output that HKDFSHA256Expand can
+	// produce, in bytes.
+	HKDFSHA256SizeMax = 255 * HKDFSHA256Size
+	// HKDFSHA512SizeMax is the largest output that HKDFSHA512Expand can
+	// produce, in bytes.
+	HKDFSHA512SizeMax = 255 * HKDFSHA512Size
+)
+
+var (
+	errSize     = errors.New("kdf: invalid subkey size")
+	errHKDFSize = errors.New("kdf: requested HKDF output is too long")
+)
+
+// DeriveFromKey derives a size-byte subkey from key, identified by subkeyID
+// and context. An error is returned if size is not between SizeMin and
+// SizeMax.
+func DeriveFromKey(size int, subkeyID uint64, context *[ContextSize]byte, key nacl.Key) ([]byte, error) {
+	if size < SizeMin || size > SizeMax {
+		return nil, errSize
+	}
+	var salt [generichash.SaltSize]byte
+	var personal [generichash.PersonalSize]byte
+	binary.LittleEndian.PutUint64(salt[:], subkeyID)
+	copy(personal[:], context[:])
+	return generichash.SumSaltPersonal(size, nil, key[:], &salt, &personal)
+}
+
+// DeriveKey derives a subkey of nacl.KeySize bytes from key, identified by
+// subkeyID and context, suitable for use with the other packages in this
+// library.
+func DeriveKey(subkeyID uint64, context *[ContextSize]byte, key nacl.Key) nacl.Key {
+	out, err := DeriveFromKey(nacl.KeySize, subkeyID, context, key)
+	if err != nil {
+		panic(err)
+	}
+	subkey := new([nacl.KeySize]byte)
+	copy(subkey[:], out)
+	return subkey
+}
+
+// HKDFSHA256Extract extracts a pseudorandom key from the input keying material
+// ikm and an optional salt, using HMAC-SHA-256.
+func HKDFSHA256Extract(salt, ikm []byte) *[HKDFSHA256Size]byte {
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		panic(err)
+	}
+	out := new([HKDFSHA256Size]byte)
+	copy(out[:], prk)
+	return out
+}
+
+// HKDFSHA256Expand derives size bytes from prk, a pseudorandom key returned by
+// HKDFSHA256Extract, and info, which binds the output to its purpose. An error
+// is returned if size is larger than HKDFSHA256SizeMax.
+func HKDFSHA256Expand(size int, info []byte, prk *[HKDFSHA256Size]byte) ([]byte, error) {
+	if size < 0 || size > HKDFSHA256SizeMax {
+		return nil, errHKDFSize
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	return hkdf.Expand(sha256.New, prk[:], string(info), size)
+}
+
+// HKDFSHA512Extract extracts a pseudorandom key from the input keying material
+// ikm and an optional salt, using HMAC-SHA-512.
+func HKDFSHA512Extract(salt, ikm []byte) *[HKDFSHA512Size]byte {
+	prk, err := hkdf.Extract(sha512.New, ikm, salt)
+	if err != nil {
+		panic(err)
+	}
+	out := new([HKDFSHA512Size]byte)
+	copy(out[:], prk)
+	return out
+}
+
+// HKDFSHA512Expand derives size bytes from prk, a pseudorandom key returned by
+// HKDFSHA512Extract, and info, which binds the output to its purpose. An error
+// is returned if size is larger than HKDFSHA512SizeMax.
+func HKDFSHA512Expand(size int, info []byte, prk *[HKDFSHA512Size]byte) ([]byte, error) {
+	if size < 0 || size > HKDFSHA512SizeMax {
+		return nil, errHKDFSize
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	return hkdf.Expand(sha512.New, prk[:], string(info), size)
+}
@@ -0,0 +1,149 @@
+package kdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Generated with libsodium 1.0.18's crypto_kdf_derive_from_key, with the
+// master key 00 01 ... 1f.
+var deriveTests = []struct {
+	size     int
+	subkeyID uint64
+	context  string
+	out      string
+}{
+	{32, 0, "Examples", "d676d6d54480f13ed75c930629f21919bf7126656e4b7f9ef045ee34ac288161"},
+	{32, 1, "Examples", "db4b973a1a3ff12de3d88891c60acf8438ed707a73b3d16dd62048c3a6e372e9"},
+	{16, 1<<64 - 1, "__auth__", "6eaf2be27bf433a605ab7e4a8201c325"},
+	{64, 42, "UserName", "1092e1b8f47933b5f3f57d7d4261dc2c4a571ea143e2ae06907fbc04ce26401161accfabdb7c01a13bf29417e32a1bff1a54de92833b1e60e5ad886a30de39a6"},
+}
+
+func TestDeriveFromKey(t *testing.T) {
+	key := new([nacl.KeySize]byte)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for _, tt := range deriveTests {
+		var context [ContextSize]byte
+		copy(context[:], tt.context)
+		out, err := DeriveFromKey(tt.size, tt.subkeyID, &context, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != tt.out {
+			t.Errorf("DeriveFromKey(%d, %d, %q): got %s, want %s", tt.size, tt.subkeyID, tt.context, got, tt.out)
+		}
+		if tt.size == nacl.KeySize {
+			if got := DeriveKey(tt.subkeyID, &context, key); !bytes.Equal(got[:], out) {
+				t.Errorf("DeriveKey(%d, %q): got %x, want %s", tt.subkeyID, tt.context, got, tt.out)
+			}
+		}
+	}
+
+	var context [ContextSize]byte
+	for _, size := range []int{0, SizeMin - 1, SizeMax + 1} {
+		if _, err := DeriveFromKey(size, 0, &context, key); err == nil {
+			t.Errorf("DeriveFromKey accepted size %d", size)
+		}
+	}
+}
+
+// The SHA-256 tests are test cases 1 to 3 from RFC 5869, Appendix A. The
+// SHA-512 tests use the same inputs, with outputs computed with Python's hmac
+// module.
+var hkdfTests = []struct {
+	ikm, salt, info string
+	size            int
+	prk256, okm256  string
+	prk512, okm512  string
+}{
+	{
+		ikm:    "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+		salt:   "000102030405060708090a0b0c",
+		info:   "f0f1f2f3f4f5f6f7f8f9",
+		size:   42,
+		prk256: "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+		okm256: "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		prk512: "665799823737ded04a88e47e54a5890bb2c3d247c7a4254a8e61350723590a26c36238127d8661b88cf80ef802d57e2f7cebcf1e00e083848be19929c61b4237",
+		okm512: "832390086cda71fb47625bb5ceb168e4c8e26a1a16ed34d9fc7fe92c1481579338da362cb8d9f925d7cb",
+	},
+	{
+		ikm:    "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f",
+		salt:   "606162636465666768696a6b6c6d6e6f707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9fa0a1a2a3a4a5a6a7a8a9aaabacadaeaf",
+		info:   "b0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7e8e9eaebecedeeeff0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+		size:   82,
+		prk256: "06a6b88c5853361a06104c9ceb35b45cef760014904671014a193f40c15fc244",
+		okm256: "b11e398dc80327a1c8e7f78c596a49344f012eda2d4efad8a050cc4c19afa97c59045a99cac7827271cb41c65e590e09da3275600c2f09b8367793a9aca3db71cc30c58179ec3e87c14c01d5c1f3434f1d87",
+	},
+	{
+		ikm:    "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+		size:   42,
+		prk256: "19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+		okm256: "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		prk512: "fd200c4987ac491313bd4a2a13287121247239e11c9ef82802044b66ef357e5b194498d0682611382348572a7b1611de54764094286320578a863f36562b0df6",
+		okm512: "f5fa02b18298a72a8c23898a8703472c6eb179dc204c03425c970e3b164bf90fff22d04836d0e2343bac",
+	},
+}
+
+func TestHKDF(t *testing.T) {
+	for i, tt := range hkdfTests {
+		ikm, salt, info := decodeHex(t, tt.ikm), decodeHex(t, tt.salt), decodeHex(t, tt.info)
+
+		prk256 := HKDFSHA256Extract(salt, ikm)
+		if got := hex.EncodeToString(prk256[:]); got != tt.prk256 {
+			t.Errorf("%d: HKDFSHA256Extract: got %s, want %s", i, got, tt.prk256)
+		}
+		okm, err := HKDFSHA256Expand(tt.size, info, prk256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(okm); got != tt.okm256 {
+			t.Errorf("%d: HKDFSHA256Expand: got %s, want %s", i, got, tt.okm256)
+		}
+
+		if tt.prk512 == "" {
+			continue
+		}
+		prk512 := HKDFSHA512Extract(salt, ikm)
+		if got := hex.EncodeToString(prk512[:]); got != tt.prk512 {
+			t.Errorf("%d: HKDFSHA512Extract: got %s, want %s", i, got, tt.prk512)
+		}
+		okm, err = HKDFSHA512Expand(tt.size, info, prk512)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(okm); got != tt.okm512 {
+			t.Errorf("%d: HKDFSHA512Expand: got %s, want %s", i, got, tt.okm512)
+		}
+	}
+}
+
+func TestHKDFExpandSize(t *testing.T) {
+	prk256 := HKDFSHA256Extract(nil, []byte("input keying material"))
+	if out, err := HKDFSHA256Expand(HKDFSHA256SizeMax, nil, prk256); err != nil || len(out) != HKDFSHA256SizeMax {
+		t.Errorf("HKDFSHA256Expand(max): got %d bytes, %v", len(out), err)
+	}
+	if _, err := HKDFSHA256Expand(HKDFSHA256SizeMax+1, nil, prk256); err == nil {
+		t.Errorf("HKDFSHA256Expand accepted an output that is too long")
+	}
+	prk512 := HKDFSHA512Extract(nil, []byte("input keying material"))
+	if out, err := HKDFSHA512Expand(0, nil, prk512); err != nil || len(out) != 0 {
+		t.Errorf("HKDFSHA512Expand(0): got %d bytes, %v", len(out), err)
+	}
+	if _, err := HKDFSHA512Expand(HKDFSHA512SizeMax+1, nil, prk512); err == nil {
+		t.Errorf("HKDFSHA512Expand accepted an output that is too long")
+	}
+}
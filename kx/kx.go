@@ -0,0 +1,98 @@
+/*
+Package kx computes a pair of shared session keys from two parties' key pairs.
+
+A client and a server each generate a key pair and exchange public keys. The
+client then calls ClientSessionKeys and the server calls ServerSessionKeys;
+each gets a key for receiving data (rx) and a key for transmitting data (tx).
+The client's tx key is the server's rx key and vice versa, so each direction
+of a bidirectional channel gets its own key and the two sides never need to
+coordinate nonces.
+
+The session keys are the two halves of BLAKE2b-512(q || client_pk || server_pk),
+where q is the Curve25519 shared secret of the two key pairs.
+
+This package is interoperable with libsodium's crypto_kx:
+https://doc.libsodium.org/key_exchange.
+*/
+package kx // import "github.com/kevinburke/nacl/kx"
+
+import (
+	"errors"
+	"io"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/scalarmult"
+	"golang.org/x/crypto/blake2b"
+)
+
+// SessionKeySize is the size, in bytes, of a session key.
+const SessionKeySize = 32
+
+var errLowOrder = errors.New("kx: peer public key has low order")
+
+// KeyPair generates a new public/private key pair using entropy from rand.
+func KeyPair(rand io.Reader) (publicKey, privateKey nacl.Key, err error) {
+	privateKey = new([nacl.KeySize]byte)
+	if _, err := io.ReadFull(rand, privateKey[:]); err != nil {
+		return nil, nil, err
+	}
+	return scalarmult.Base(privateKey), privateKey, nil
+}
+
+// SeedKeyPair deterministically derives a public/private key pair from seed.
+// The private key is BLAKE2b-256 of the seed.
+func SeedKeyPair(seed nacl.Key) (publicKey, privateKey nacl.Key) {
+	sk := blake2b.Sum256(seed[:])
+	privateKey = &sk
+	return scalarmult.Base(privateKey), privateKey
+}
+
+// sessionKeys returns BLAKE2b-512(q || clientPublicKey || serverPublicKey),
+// where q is the shared secret between privateKey and peersPublicKey.
+func sessionKeys(peersPublicKey, privateKey, clientPublicKey, serverPublicKey nacl.Key) (*[blake2b.Size]byte, error) {
+	q, err := scalarmult.MultChecked(privateKey, peersPublicKey)
+	if err != nil {
+		return nil, errLowOrder
+	}
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		panic(err)
+	}
+	h.Write(q[:])
+	h.Write(clientPublicKey[:])
+	h.Write(serverPublicKey[:])
+	keys := new([blake2b.Size]byte)
+	h.Sum(keys[:0])
+	clear(q[:])
+	return keys, nil
+}
+
+// ClientSessionKeys computes the client's session keys: rx for decrypting
+// data sent by the server, and tx for encrypting data sent to the server. It
+// returns an error if serverPublicKey is a low-order point, which would make
+// the keys predictable.
+func ClientSessionKeys(clientPublicKey, clientPrivateKey, serverPublicKey nacl.Key) (rx, tx nacl.Key, err error) {
+	keys, err := sessionKeys(serverPublicKey, clientPrivateKey, clientPublicKey, serverPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	rx, tx = new([SessionKeySize]byte), new([SessionKeySize]byte)
+	copy(rx[:], keys[:SessionKeySize])
+	copy(tx[:], keys[SessionKeySize:])
+	return rx, tx, nil
+}
+
+// ServerSessionKeys computes the server's session keys: rx for decrypting
+// data sent by the client, and tx for encrypting data sent to the client. It
+// returns an error if clientPublicKey is a low-order point, which would make
+// the keys predictable.
+func ServerSessionKeys(serverPublicKey, serverPrivateKey, clientPublicKey nacl.Key) (rx, tx nacl.Key, err error) {
+	keys, err := sessionKeys(clientPublicKey, serverPrivateKey, clientPublicKey, serverPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	rx, tx = new([SessionKeySize]byte), new([SessionKeySize]byte)
+	copy(tx[:], keys[:SessionKeySize])
+	copy(rx[:], keys[SessionKeySize:])
+	return rx, tx, nil
+}
@@ -0,0 +1,99 @@
+package kx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+)
+
+func decodeKey(t *testing.T, s string) nacl.Key {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != nacl.KeySize {
+		t.Fatalf("bad key %q", s)
+	}
+	key := new([nacl.KeySize]byte)
+	copy(key[:], b)
+	return key
+}
+
+func TestLibsodium(t *testing.T) {
+	// Generated with libsodium's crypto_kx_seed_keypair and
+	// crypto_kx_client_session_keys, with the seeds 0x00..0x1f for the
+	// client and 0x01..0x20 for the server.
+	clientSeed, serverSeed := new([32]byte), new([32]byte)
+	for i := range clientSeed {
+		clientSeed[i] = byte(i)
+		serverSeed[i] = byte(i + 1)
+	}
+	clientPublicKey, clientPrivateKey := SeedKeyPair(clientSeed)
+	serverPublicKey, serverPrivateKey := SeedKeyPair(serverSeed)
+	if *clientPublicKey != *decodeKey(t, "0e0216223f147143d32615a91189c288c1728cba3cc5f9f621b1026e03d83129") {
+		t.Errorf("SeedKeyPair: wrong client public key %x", clientPublicKey)
+	}
+	if *clientPrivateKey != *decodeKey(t, "cb2f5160fc1f7e05a55ef49d340b48da2e5a78099d53393351cd579dd42503d6") {
+		t.Errorf("SeedKeyPair: wrong client private key %x", clientPrivateKey)
+	}
+	if *serverPublicKey != *decodeKey(t, "57f56a5f1982c762c37291a4ec8850fb94d83a171a67c9d326ff53c6998e4825") {
+		t.Errorf("SeedKeyPair: wrong server public key %x", serverPublicKey)
+	}
+
+	wantClientRx := decodeKey(t, "e0307e1a80dcccabd25e85575de18e93f7555d157c4f46ebe7dab0e510e831c2")
+	wantClientTx := decodeKey(t, "08bf5f69b29ce4dba39fd0cb2bf0d4a4e768463ff2b4b95c45ee3c0ccbaae022")
+
+	rx, tx, err := ClientSessionKeys(clientPublicKey, clientPrivateKey, serverPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *rx != *wantClientRx || *tx != *wantClientTx {
+		t.Errorf("ClientSessionKeys: got rx %x, tx %x", rx, tx)
+	}
+	rx, tx, err = ServerSessionKeys(serverPublicKey, serverPrivateKey, clientPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *rx != *wantClientTx || *tx != *wantClientRx {
+		t.Errorf("ServerSessionKeys: got rx %x, tx %x", rx, tx)
+	}
+}
+
+func TestSessionKeys(t *testing.T) {
+	clientPublicKey, clientPrivateKey, err := KeyPair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverPublicKey, serverPrivateKey, err := KeyPair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientRx, clientTx, err := ClientSessionKeys(clientPublicKey, clientPrivateKey, serverPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverRx, serverTx, err := ServerSessionKeys(serverPublicKey, serverPrivateKey, clientPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *clientRx != *serverTx || *clientTx != *serverRx {
+		t.Errorf("client and server session keys do not match")
+	}
+	if *clientRx == *clientTx {
+		t.Errorf("rx and tx keys are equal")
+	}
+}
+
+func TestLowOrder(t *testing.T) {
+	publicKey, privateKey, err := KeyPair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zero [32]byte
+	if _, _, err := ClientSessionKeys(publicKey, privateKey, &zero); err == nil {
+		t.Errorf("ClientSessionKeys: expected error for low order server key, got nil")
+	}
+	if _, _, err := ServerSessionKeys(publicKey, privateKey, &zero); err == nil {
+		t.Errorf("ServerSessionKeys: expected error for low order client key, got nil")
+	}
+}
@@ -47,6 +47,8 @@ type Key *[KeySize]byte
 // larger public key uses nonce 2 for its first message to the other key, nonce
 // 4 for its second message, nonce 6 for its third message, etc. Nonces are long
 // enough that randomly generated nonces have negligible risk of collision.
+// Alternatively, the kx package derives a separate key for each direction, so
+// each side can number its own messages independently.
 type Nonce *[NonceSize]byte
 
 // Load decodes a 64-byte hex string into a Key. A hex key is suitable for
@@ -142,7 +144,8 @@ const HashSize = sha512.Size
 // In particular, the crypto_hash function is designed to make finding
 // collisions difficult.
 //
-// Hash is currently an implementation of SHA-512.
+// Hash is currently an implementation of SHA-512. For BLAKE2b, as used by
+// libsodium's crypto_generichash, see the generichash package.
 func Hash(m []byte) *[HashSize]byte {
 	out := sha512.Sum512(m)
 	return &out
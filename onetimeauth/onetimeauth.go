@@ -20,8 +20,7 @@ package onetimeauth
 
 import (
 	"github.com/kevinburke/nacl"
-	//lint:ignore SA1019 Poly1305 usage is safe for our specific cryptographic building block use case
-	"golang.org/x/crypto/poly1305"
+	"github.com/kevinburke/nacl/internal/poly1305"
 )
 
 // Size is the size, in bytes, of the result of a call to Sum.
@@ -42,3 +41,44 @@ func Sum(m []byte, key nacl.Key) *[Size]byte {
 func Verify(mac *[Size]byte, m []byte, key nacl.Key) bool {
 	return poly1305.Verify(mac, m, key)
 }
+
+// MAC is an incremental Poly1305 authenticator, for authenticating a message
+// that arrives in pieces or is too large to hold in memory. It is the
+// equivalent of libsodium's crypto_onetimeauth_state. Writing the pieces of a
+// message to a MAC and calling Sum produces the same authenticator as calling
+// Sum on the whole message.
+//
+// The one-time key restriction still applies: a key must be used with only
+// one MAC, and a MAC must be used to authenticate only one message.
+type MAC struct {
+	mac *poly1305.MAC
+}
+
+// New returns a new MAC that authenticates a message with the given one-time
+// key.
+func New(key nacl.Key) *MAC {
+	return &MAC{mac: poly1305.New(key)}
+}
+
+// Size returns the size, in bytes, of the authenticator produced by Sum.
+func (m *MAC) Size() int { return Size }
+
+// Write adds more data to the message being authenticated. It never returns an
+// error. Write panics if it is called after Sum or Verify.
+func (m *MAC) Write(p []byte) (n int, err error) {
+	return m.mac.Write(p)
+}
+
+// Sum appends the authenticator for the data written so far to b and returns
+// the resulting slice. Sum finalizes the MAC: after it, only Sum and Verify
+// may be called, and calling Write panics.
+func (m *MAC) Sum(b []byte) []byte {
+	return m.mac.Sum(b)
+}
+
+// Verify reports whether mac is the authenticator for the data written so
+// far, without leaking timing information. Like Sum, Verify finalizes the
+// MAC.
+func (m *MAC) Verify(mac *[Size]byte) bool {
+	return m.mac.Verify(mac[:])
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/kevinburke/nacl/randombytes"
+	//lint:ignore SA1019 used only to check the internal implementation against it
+	"golang.org/x/crypto/poly1305"
 )
 
 var key1 = &[32]byte{
@@ -84,3 +86,72 @@ func Test7(t *testing.T) {
 		}
 	}
 }
+
+func TestMAC(t *testing.T) {
+	// Write msg1 in every possible pair of pieces, including empty ones.
+	for split := 0; split <= len(msg1); split++ {
+		mac := New(key1)
+		mac.Write(msg1[:split])
+		mac.Write(msg1[split:])
+		if !mac.Verify(&sum1) {
+			t.Errorf("split %d: Verify: got false, want true", split)
+		}
+		var out [Size]byte
+		if got := mac.Sum(out[:0]); string(got) != string(sum1[:]) {
+			t.Errorf("split %d: Sum: got %x, want %x", split, got, sum1)
+		}
+	}
+
+	mac := New(key1)
+	for _, b := range msg1 {
+		mac.Write([]byte{b})
+	}
+	if got := mac.Sum([]byte("prefix")); string(got) != "prefix"+string(sum1[:]) {
+		t.Errorf("Sum did not append the authenticator: got %x", got)
+	}
+	bad := sum1
+	bad[0] ^= 1
+	if mac.Verify(&bad) {
+		t.Errorf("Verify accepted an invalid authenticator")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Write after Sum did not panic")
+		}
+	}()
+	mac.Write(msg1)
+}
+
+// FuzzSum checks Sum, Verify and MAC against golang.org/x/crypto/poly1305,
+// which this package used before it had its own copy of the implementation.
+func FuzzSum(f *testing.F) {
+	f.Add(key1[:], msg1, uint(0))
+	f.Add(key1[:], msg1, uint(17))
+	f.Add(make([]byte, 32), []byte{}, uint(0))
+	f.Fuzz(func(t *testing.T, keyBytes, msg []byte, split uint) {
+		if len(keyBytes) != 32 {
+			t.Skip()
+		}
+		key := new([32]byte)
+		copy(key[:], keyBytes)
+		var want [Size]byte
+		poly1305.Sum(&want, msg, key)
+
+		if got := Sum(msg, key); *got != want {
+			t.Fatalf("Sum: got %x, want %x", got, want)
+		}
+		if !Verify(&want, msg, key) {
+			t.Fatalf("Verify: rejected the x/crypto authenticator")
+		}
+
+		// Write the message in chunks of size split+1.
+		mac := New(key)
+		for rest, n := msg, int(split%64)+1; len(rest) > 0; rest = rest[min(n, len(rest)):] {
+			mac.Write(rest[:min(n, len(rest))])
+		}
+		if got := mac.Sum(nil); string(got) != string(want[:]) {
+			t.Fatalf("MAC.Sum: got %x, want %x", got, want)
+		}
+	})
+}
@@ -0,0 +1,233 @@
+/*
+Package pwhash derives keys from passwords, and hashes passwords for storage.
+
+Passwords are low-entropy and easy to guess, so they should never be used
+directly as keys. Key and ScryptKey use deliberately slow, memory-hard
+functions to turn a password and a random salt into a nacl.Key. The cost is
+controlled by an operations limit and a memory limit; the
+OpsLimitInteractive/MemLimitInteractive, OpsLimitModerate/MemLimitModerate and
+OpsLimitSensitive/MemLimitSensitive presets are a reasonable place to start.
+
+Str hashes a password into an ASCII string that contains the salt and the
+parameters as well as the hash, suitable for storing in a database, and
+StrVerify checks a password against it.
+
+The default algorithm is Argon2id, version 1.3. This package is interoperable
+with libsodium's crypto_pwhash_argon2id and crypto_pwhash_scryptsalsa208sha256:
+https://doc.libsodium.org/password_hashing.
+*/
+package pwhash // import "github.com/kevinburke/nacl/pwhash"
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/randombytes"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// SaltSize is the size, in bytes, of the salt used by Key.
+	SaltSize = 16
+
+	// OpsLimitMin is the smallest operations limit accepted by Key.
+	OpsLimitMin = 1
+	// MemLimitMin is the smallest memory limit, in bytes, accepted by Key.
+	MemLimitMin = 8192
+
+	// OpsLimitInteractive and MemLimitInteractive are suitable for
+	// interactive, online operations. Deriving a key requires 64 MiB of RAM.
+	OpsLimitInteractive = 2
+	MemLimitInteractive = 64 << 20
+
+	// OpsLimitModerate and MemLimitModerate are slower than the interactive
+	// presets, and require 256 MiB of RAM.
+	OpsLimitModerate = 3
+	MemLimitModerate = 256 << 20
+
+	// OpsLimitSensitive and MemLimitSensitive are suitable for sensitive,
+	// non-interactive operations. Deriving a key requires 1 GiB of RAM and
+	// may take several seconds.
+	OpsLimitSensitive = 4
+	MemLimitSensitive = 1 << 30
+
+	// StrPrefix is the prefix of every string produced by Str.
+	StrPrefix = "$argon2id$"
+
+	// memLimitMax is the largest memory limit that fits in Argon2's 32-bit
+	// count of KiB blocks.
+	memLimitMax = (1<<32 - 1) * 1024
+	opsLimitMax = 1<<32 - 1
+	strHashSize = 32
+)
+
+var (
+	errOpsLimitTooSmall = errors.New("pwhash: operations limit too small")
+	errOpsLimitTooLarge = errors.New("pwhash: operations limit too large")
+	errMemLimitTooSmall = errors.New("pwhash: memory limit too small")
+	errMemLimitTooLarge = errors.New("pwhash: memory limit too large")
+	errInvalidStr       = errors.New("pwhash: invalid password hash string")
+)
+
+func checkLimits(opsLimit, memLimit uint64) error {
+	if opsLimit < OpsLimitMin {
+		return errOpsLimitTooSmall
+	}
+	if opsLimit > opsLimitMax {
+		return errOpsLimitTooLarge
+	}
+	if memLimit < MemLimitMin {
+		return errMemLimitTooSmall
+	}
+	if memLimit > memLimitMax {
+		return errMemLimitTooLarge
+	}
+	return nil
+}
+
+// argon2id derives a keyLen-byte key with libsodium's parameter conventions:
+// memLimit is in bytes, and a single lane is used.
+func argon2id(password, salt []byte, opsLimit, memLimit uint64, keyLen uint32) []byte {
+	return argon2.IDKey(password, salt, uint32(opsLimit), uint32(memLimit/1024), 1, keyLen)
+}
+
+// Key derives a key from password and salt using Argon2id. opsLimit is the
+// number of passes over memory, and memLimit is the amount of memory to use,
+// in bytes. The same password, salt and limits always produce the same key;
+// the salt should be random and unique per password, and stored alongside the
+// derived key's ciphertext.
+func Key(password []byte, salt *[SaltSize]byte, opsLimit, memLimit uint64) (nacl.Key, error) {
+	if err := checkLimits(opsLimit, memLimit); err != nil {
+		return nil, err
+	}
+	key := new([nacl.KeySize]byte)
+	copy(key[:], argon2id(password, salt[:], opsLimit, memLimit, nacl.KeySize))
+	return key, nil
+}
+
+// Str returns an ASCII string containing the Argon2id hash of password, a
+// randomly generated salt, and the parameters used to compute the hash, in the
+// format
+//
+//	$argon2id$v=19$m=<memLimit in KiB>,t=<opsLimit>,p=1$<salt>$<hash>
+//
+// where the salt and hash are unpadded standard base64. Str panics if it
+// cannot read enough random data.
+func Str(password []byte, opsLimit, memLimit uint64) (string, error) {
+	if err := checkLimits(opsLimit, memLimit); err != nil {
+		return "", err
+	}
+	var salt [SaltSize]byte
+	randombytes.MustRead(salt[:])
+	hash := argon2id(password, salt[:], opsLimit, memLimit, strHashSize)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=1$%s$%s", StrPrefix, argon2.Version,
+		memLimit/1024, opsLimit,
+		base64.RawStdEncoding.EncodeToString(salt[:]),
+		base64.RawStdEncoding.EncodeToString(hash)), nil
+}
+
+type params struct {
+	memory  uint32 // in KiB
+	time    uint32
+	threads uint8
+	salt    []byte
+	hash    []byte
+}
+
+func parseStr(str string) (*params, error) {
+	rest, ok := strings.CutPrefix(str, StrPrefix)
+	if !ok {
+		return nil, errInvalidStr
+	}
+	parts := strings.Split(rest, "$")
+	if len(parts) != 4 {
+		return nil, errInvalidStr
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errInvalidStr
+	}
+	p := new(params)
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, errInvalidStr
+	}
+	// Reject anything Sscanf would have silently ignored, so there is only
+	// one valid encoding of a given set of parameters.
+	if parts[1] != fmt.Sprintf("m=%d,t=%d,p=%d", p.memory, p.time, p.threads) {
+		return nil, errInvalidStr
+	}
+	if p.time < OpsLimitMin || p.threads < 1 || uint64(p.memory)*1024 < MemLimitMin {
+		return nil, errInvalidStr
+	}
+	// Argon2 requires at least 8 KiB of memory per lane; like libsodium,
+	// reject strings that ask for less rather than silently using more.
+	if uint64(p.memory) < 8*uint64(p.threads) {
+		return nil, errInvalidStr
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.Strict().DecodeString(parts[2]); err != nil || len(p.salt) < 8 {
+		return nil, errInvalidStr
+	}
+	if p.hash, err = base64.RawStdEncoding.Strict().DecodeString(parts[3]); err != nil || len(p.hash) < 16 {
+		return nil, errInvalidStr
+	}
+	return p, nil
+}
+
+// StrVerify reports whether password matches str, a string produced by Str
+// or by libsodium's crypto_pwhash_str. It returns false if str is not a valid
+// password hash string. StrVerify does not leak timing information about the
+// hash.
+//
+// Verifying a string costs as much as the parameters stored in it, which may
+// be up to 4 TiB of memory and 2^32-1 passes over it, so only pass StrVerify
+// strings from a trusted source, like your own database. Use
+// StrVerifyWithLimits to verify a string that may have been tampered with.
+func StrVerify(str string, password []byte) bool {
+	p, err := parseStr(str)
+	if err != nil {
+		return false
+	}
+	return verify(p, password)
+}
+
+// StrVerifyWithLimits is like StrVerify, but also returns false, without
+// computing the hash, if str asks for more than opsLimit passes or memLimit
+// bytes of memory. Verifying a string that is accepted allocates at most
+// memLimit bytes and performs at most opsLimit passes over them, split
+// across no more than 255 goroutines, one per lane named in str.
+func StrVerifyWithLimits(str string, password []byte, opsLimit, memLimit uint64) bool {
+	p, err := parseStr(str)
+	if err != nil {
+		return false
+	}
+	if uint64(p.time) > opsLimit || uint64(p.memory)*1024 > memLimit {
+		return false
+	}
+	return verify(p, password)
+}
+
+func verify(p *params, password []byte) bool {
+	hash := argon2.IDKey(password, p.salt, p.time, p.memory, p.threads, uint32(len(p.hash)))
+	return subtle.ConstantTimeCompare(hash, p.hash) == 1
+}
+
+// StrNeedsRehash reports whether str, a string produced by Str, was computed
+// with parameters other than opsLimit and memLimit. If so, the password should
+// be hashed again with the current parameters the next time the user logs
+// in. An error is returned if str is not a valid Argon2id password hash
+// string.
+func StrNeedsRehash(str string, opsLimit, memLimit uint64) (bool, error) {
+	if err := checkLimits(opsLimit, memLimit); err != nil {
+		return false, err
+	}
+	p, err := parseStr(str)
+	if err != nil {
+		return false, err
+	}
+	return uint64(p.time) != opsLimit || uint64(p.memory) != memLimit/1024, nil
+}
@@ -0,0 +1,169 @@
+package pwhash
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Test vectors were generated with libsodium 1.0.18.
+var argon2idTests = []struct {
+	opsLimit, memLimit uint64
+	out                string
+}{
+	{1, 8192, "3bfa796a86260b73457e1290b51695931a17e266f601d0fb45563e282fb6865b"},
+	{2, 65536, "9c87c06898bc18516669b54a8b95d36e622946494d7a55c26f391a0406068c19"},
+	{3, 1 << 20, "2cdfc6b7d9c2f719e4f1ec7697c38e6ad4b023e6d446602ac3d0dcb2571ca5fa"},
+}
+
+func TestKey(t *testing.T) {
+	var salt [SaltSize]byte
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	for _, tt := range argon2idTests {
+		key, err := Key([]byte("correct horse"), &salt, tt.opsLimit, tt.memLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key[:]); got != tt.out {
+			t.Errorf("Key(%d, %d): got %s, want %s", tt.opsLimit, tt.memLimit, got, tt.out)
+		}
+	}
+}
+
+func TestKeyLimits(t *testing.T) {
+	var salt [SaltSize]byte
+	if _, err := Key(nil, &salt, 0, MemLimitMin); err == nil {
+		t.Errorf("expected error for opsLimit 0, got nil")
+	}
+	if _, err := Key(nil, &salt, OpsLimitMin, MemLimitMin-1); err == nil {
+		t.Errorf("expected error for small memLimit, got nil")
+	}
+	if _, err := Key(nil, &salt, 1<<32, MemLimitMin); err == nil {
+		t.Errorf("expected error for large opsLimit, got nil")
+	}
+}
+
+var scryptTests = []struct {
+	opsLimit, memLimit uint64
+	out                string
+}{
+	{32768, 16 << 20, "1fa1348854818a9e4e9037d04148d79bf80ee7ccc89564199957a2a783532f80"},
+	{ScryptOpsLimitInteractive, ScryptMemLimitInteractive, "f5b7864f3c8503cb1652e6a71f5278bfcff1c0b52c9f12ac7c37249412010867"},
+	{1000000, 1 << 20, "3934dc9c89b3d7ffc1f11a4bb8c964a5870198991ebc01f224ff127062b94040"},
+	{32768, 1 << 30, "1fa1348854818a9e4e9037d04148d79bf80ee7ccc89564199957a2a783532f80"},
+}
+
+func TestScryptKey(t *testing.T) {
+	var salt [ScryptSaltSize]byte
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	for _, tt := range scryptTests {
+		key, err := ScryptKey([]byte("correct horse"), &salt, tt.opsLimit, tt.memLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key[:]); got != tt.out {
+			t.Errorf("ScryptKey(%d, %d): got %s, want %s", tt.opsLimit, tt.memLimit, got, tt.out)
+		}
+	}
+}
+
+func TestStrVerify(t *testing.T) {
+	// Generated with libsodium's crypto_pwhash_argon2id_str.
+	const libsodium = "$argon2id$v=19$m=64,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk"
+	if !StrVerify(libsodium, []byte("correct horse")) {
+		t.Errorf("StrVerify: could not verify libsodium hash")
+	}
+	if StrVerify(libsodium, []byte("battery staple")) {
+		t.Errorf("StrVerify: verified the wrong password")
+	}
+
+	str, err := Str([]byte("correct horse"), 2, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(str, "$argon2id$v=19$m=64,t=2,p=1$") {
+		t.Errorf("Str: unexpected format %q", str)
+	}
+	if !StrVerify(str, []byte("correct horse")) {
+		t.Errorf("StrVerify: could not verify %q", str)
+	}
+	str2, err := Str([]byte("correct horse"), 2, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str == str2 {
+		t.Errorf("Str: two hashes of the same password are equal")
+	}
+
+	invalid := []string{
+		"",
+		"$argon2i$v=19$m=64,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+		"$argon2id$v=16$m=64,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+		"$argon2id$v=19$m=64,t=0,p=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+		"$argon2id$v=19$m=64,t=2,p=1,x=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+		"$argon2id$v=19$m=64,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ==$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+		"$argon2id$v=19$m=64,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ",
+		// Less than 8 KiB of memory per lane.
+		"$argon2id$v=19$m=64,t=2,p=9$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk",
+	}
+	for _, s := range invalid {
+		if StrVerify(s, []byte("correct horse")) {
+			t.Errorf("StrVerify: accepted invalid string %q", s)
+		}
+		if _, err := StrNeedsRehash(s, 2, 65536); err == nil {
+			t.Errorf("StrNeedsRehash: accepted invalid string %q", s)
+		}
+	}
+}
+
+func TestStrVerifyWithLimits(t *testing.T) {
+	// Generated with libsodium's crypto_pwhash_str_alg, with opsLimit 20 and
+	// memLimit 8 MiB.
+	const libsodium = "$argon2id$v=19$m=8192,t=20,p=1$6rS5aB/d+3ZZf3YoseNoqw$mPDqUYTdRRouxY4EYF8gfCcubqJcr5ANyrb9fdnOONo"
+	if !StrVerify(libsodium, []byte("correct horse")) {
+		t.Errorf("StrVerify: could not verify libsodium hash")
+	}
+	if !StrVerifyWithLimits(libsodium, []byte("correct horse"), 20, 8<<20) {
+		t.Errorf("StrVerifyWithLimits: could not verify libsodium hash within its limits")
+	}
+	if StrVerifyWithLimits(libsodium, []byte("correct horse"), 19, 8<<20) {
+		t.Errorf("StrVerifyWithLimits: accepted a hash above opsLimit")
+	}
+	if StrVerifyWithLimits(libsodium, []byte("correct horse"), 20, 8<<20-1024) {
+		t.Errorf("StrVerifyWithLimits: accepted a hash above memLimit")
+	}
+	// Would need 4 TiB of memory if StrVerifyWithLimits didn't reject it.
+	const huge = "$argon2id$v=19$m=4294967295,t=2,p=1$0xhzpsmV1+xKPmy9DEYiCQ$JhLFS5xUcP3SejAY8Kkv3+tR1DUsYAlddZCv5MT+lhk"
+	if StrVerifyWithLimits(huge, []byte("correct horse"), OpsLimitSensitive, MemLimitSensitive) {
+		t.Errorf("StrVerifyWithLimits: accepted a hash above memLimit")
+	}
+}
+
+func TestStrNeedsRehash(t *testing.T) {
+	str, err := Str([]byte("correct horse"), 2, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		opsLimit, memLimit uint64
+		want               bool
+	}{
+		{2, 65536, false},
+		{3, 65536, true},
+		{2, 131072, true},
+		{OpsLimitInteractive, MemLimitInteractive, true},
+	}
+	for _, tt := range tests {
+		got, err := StrNeedsRehash(str, tt.opsLimit, tt.memLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("StrNeedsRehash(%d, %d): got %t, want %t", tt.opsLimit, tt.memLimit, got, tt.want)
+		}
+	}
+}
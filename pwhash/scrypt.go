@@ -0,0 +1,69 @@
+package pwhash
+
+import (
+	"github.com/kevinburke/nacl"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// ScryptSaltSize is the size, in bytes, of the salt used by ScryptKey.
+	ScryptSaltSize = 32
+
+	// ScryptOpsLimitInteractive and ScryptMemLimitInteractive are suitable
+	// for interactive, online operations. Deriving a key requires 16 MiB of
+	// RAM.
+	ScryptOpsLimitInteractive = 524288
+	ScryptMemLimitInteractive = 16 << 20
+
+	// ScryptOpsLimitSensitive and ScryptMemLimitSensitive are suitable for
+	// sensitive, non-interactive operations. Deriving a key requires 1 GiB of
+	// RAM.
+	ScryptOpsLimitSensitive = 33554432
+	ScryptMemLimitSensitive = 1 << 30
+)
+
+// scryptParams converts an operations and memory limit into scrypt's N, r
+// and p parameters, the same way libsodium does.
+func scryptParams(opsLimit, memLimit uint64) (logN uint, r, p int) {
+	if opsLimit < 32768 {
+		opsLimit = 32768
+	}
+	r = 8
+	var maxN uint64
+	if opsLimit < memLimit/32 {
+		p = 1
+		maxN = opsLimit / uint64(r*4)
+	} else {
+		maxN = memLimit / uint64(r*128)
+	}
+	for logN = 1; logN < 63; logN++ {
+		if uint64(1)<<logN > maxN/2 {
+			break
+		}
+	}
+	if p == 0 {
+		maxrp := (opsLimit / 4) / (uint64(1) << logN)
+		if maxrp > 0x3fffffff {
+			maxrp = 0x3fffffff
+		}
+		p = int(maxrp) / r
+	}
+	return logN, r, p
+}
+
+// ScryptKey derives a key from password and salt using scrypt, with
+// parameters chosen from opsLimit and memLimit (in bytes). The same password,
+// salt and limits always produce the same key.
+//
+// Use Key for new applications; ScryptKey is for compatibility with existing
+// data protected with libsodium's crypto_pwhash_scryptsalsa208sha256.
+func ScryptKey(password []byte, salt *[ScryptSaltSize]byte, opsLimit, memLimit uint64) (nacl.Key, error) {
+	logN, r, p := scryptParams(opsLimit, memLimit)
+	out, err := scrypt.Key(password, salt[:], 1<<logN, r, p, nacl.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	key := new([nacl.KeySize]byte)
+	copy(key[:], out)
+	return key, nil
+}
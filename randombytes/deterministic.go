@@ -0,0 +1,85 @@
+package randombytes
+
+import (
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// SeedSize is the size, in bytes, of the seed passed to Deterministic.
+const SeedSize = 32
+
+// drgNonce is the ChaCha20 nonce libsodium's randombytes_buf_deterministic
+// uses with the seed.
+var drgNonce = []byte("LibsodiumDRG")
+
+// maxDeterministic is the number of bytes in a ChaCha20 keystream with a
+// 32-bit block counter.
+const maxDeterministic = 64 << 32
+
+type deterministic struct {
+	c    *chacha20.Cipher
+	left uint64
+}
+
+// Deterministic returns a reader that produces a stream of bytes that
+// depends only on seed. The first n bytes read from it are the same as the
+// output of libsodium's randombytes_buf_deterministic with the same seed and
+// size n: the ChaCha20 (RFC 8439) keystream for seed and the nonce
+// "LibsodiumDRG". A nacl.Key can be passed directly as the seed.
+//
+// The bytes are indistinguishable from random to anyone who doesn't know
+// the seed, so a Deterministic reader is suitable for generating
+// reproducible test fixtures or simulations. Don't use the same seed for
+// anything that needs real randomness, like keys or nonces.
+//
+// The reader returns io.EOF after 256 GiB.
+func Deterministic(seed *[SeedSize]byte) io.Reader {
+	c, err := chacha20.NewUnauthenticatedCipher(seed[:], drgNonce)
+	if err != nil {
+		panic(err)
+	}
+	return &deterministic{c: c, left: maxDeterministic}
+}
+
+func (d *deterministic) Read(p []byte) (int, error) {
+	if d.left == 0 {
+		return 0, io.EOF
+	}
+	if uint64(len(p)) > d.left {
+		p = p[:d.left]
+	}
+	clear(p)
+	d.c.XORKeyStream(p, p)
+	d.left -= uint64(len(p))
+	return len(p), nil
+}
+
+// Random returns a random uint32, the same as libsodium's
+// randombytes_random. It panics if the system's random number generator
+// fails.
+func Random() uint32 {
+	var b [4]byte
+	MustRead(b[:])
+	return binary.LittleEndian.Uint32(b[:])
+}
+
+// Uniform returns a uniformly distributed random number between 0 and
+// upperBound-1, inclusive, the same as libsodium's randombytes_uniform.
+// Unlike Random()%upperBound, the result is not biased towards small
+// numbers when upperBound is not a power of 2. Uniform returns 0 if
+// upperBound is less than 2.
+func Uniform(upperBound uint32) uint32 {
+	if upperBound < 2 {
+		return 0
+	}
+	// Reject values below 2^32 mod upperBound, so that the number of
+	// accepted values is a multiple of upperBound.
+	threshold := -upperBound % upperBound
+	for {
+		if r := Random(); r >= threshold {
+			return r % upperBound
+		}
+	}
+}
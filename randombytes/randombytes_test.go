@@ -1,7 +1,9 @@
 package randombytes
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -41,3 +43,76 @@ func TestMustRead(t *testing.T) {
 		}
 	}
 }
+
+func TestDeterministic(t *testing.T) {
+	var seed [SeedSize]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	// Generated with libsodium's randombytes_buf_deterministic.
+	const want = "0d8e6cc68715648926732e7ea73250cfaf2d58422083904c841a8ba33b986111f346ba50723a68ae283524a6bded09f83be6b80595856f72e25b86918e8b114bafb94bc8abedd73daab454576b7c5833eb0bf982a1bb4587a5c970ff0810ca3b791d7e12"
+	got := make([]byte, 100)
+	if _, err := io.ReadFull(Deterministic(&seed), got); err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(got) != want {
+		t.Errorf("Deterministic: got %x, want %s", got, want)
+	}
+
+	// Reads of any size continue the same stream.
+	r := Deterministic(&seed)
+	for i := 0; i < len(got); {
+		n, err := r.Read(got[i:min(i+7, len(got))])
+		if err != nil {
+			t.Fatal(err)
+		}
+		i += n
+	}
+	if hex.EncodeToString(got) != want {
+		t.Errorf("Deterministic in pieces: got %x, want %s", got, want)
+	}
+
+	seed[0] ^= 1
+	io.ReadFull(Deterministic(&seed), got)
+	if hex.EncodeToString(got) == want {
+		t.Errorf("Deterministic: different seeds produced the same output")
+	}
+}
+
+func TestUniform(t *testing.T) {
+	for _, bound := range []uint32{0, 1} {
+		if got := Uniform(bound); got != 0 {
+			t.Errorf("Uniform(%d): got %d, want 0", bound, got)
+		}
+	}
+	for _, bound := range []uint32{2, 3, 10, 1<<31 + 1, 1<<32 - 1} {
+		for range 1000 {
+			if got := Uniform(bound); got >= bound {
+				t.Fatalf("Uniform(%d): got %d", bound, got)
+			}
+		}
+	}
+
+	// Every value should come up when the bound is small.
+	var seen [10]int
+	for range 10000 {
+		seen[Uniform(10)]++
+	}
+	for i, n := range seen {
+		if n < 800 || n > 1200 {
+			t.Errorf("Uniform(10): got %d %d times out of 10000", i, n)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	var or, and uint32 = 0, 1<<32 - 1
+	for range 100 {
+		r := Random()
+		or |= r
+		and &= r
+	}
+	if or != 1<<32-1 || and != 0 {
+		t.Errorf("Random: bits never changed: or %#x, and %#x", or, and)
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Package ristretto255 implements the ristretto255 prime-order group, and
+arithmetic on its scalars.
+
+ristretto255 is built on Curve25519, but unlike Curve25519 it has no small
+order elements and each element has exactly one encoding, which makes it
+suitable for building higher-level protocols such as zero-knowledge proofs,
+OPRFs and PAKEs. Elements and scalars are passed around as 32-byte arrays.
+
+This package is interoperable with libsodium's crypto_core_ristretto255 and
+crypto_scalarmult_ristretto255:
+https://doc.libsodium.org/advanced/point-arithmetic/ristretto. The group is
+specified in RFC 9496.
+*/
+package ristretto255 // import "github.com/kevinburke/nacl/ristretto255"
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/kevinburke/nacl/internal/scalar"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+const (
+	// Size is the size, in bytes, of an encoded element.
+	Size = 32
+	// HashSize is the size, in bytes, of the input to FromHash.
+	HashSize = 64
+	// ScalarSize is the size, in bytes, of a scalar.
+	ScalarSize = scalar.Size
+	// NonReducedScalarSize is the size, in bytes, of the input to
+	// ScalarReduce.
+	NonReducedScalarSize = scalar.WideSize
+)
+
+var (
+	errInvalidElement = errors.New("ristretto255: invalid element encoding")
+	errIdentity       = errors.New("ristretto255: result is the identity element")
+)
+
+func fieldElement(s string) *field.Element {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	e, err := new(field.Element).SetBytes(b)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Constants from RFC 9496, Section 4.1, in little-endian form.
+var (
+	one            = new(field.Element).One()
+	d              = fieldElement("a3785913ca4deb75abd841414d0a700098e879777940c78c73fe6f2bee6c0352")
+	sqrtM1         = fieldElement("b0a00e4a271beec478e42fad0618432fa7d7fb3d99004d2b0bdfc14f8024832b")
+	sqrtADMinusOne = fieldElement("1b2e7b49a0f6977ebd54781b0c8e9daffdd1f531c9fc3c0fac48832bbf316937")
+	invSqrtAMinusD = fieldElement("ea405d80aafdc899be72415a17162f9d40d801fe917bc216a2fcafcf05896c78")
+	oneMinusDSQ    = fieldElement("76c15f94c1097ce20f355ecd38a1812ce4df70beddab9499d7e0b3b2a8729002")
+	dMinusOneSQ    = fieldElement("204ded44aa5aad3199191eb02c4a9ed2eb4e9b522fd3dc4c41226cf67ab36859")
+)
+
+// decode decodes an element, following RFC 9496, Section 4.3.1.
+func decode(in *[Size]byte) (*edwards25519.Point, error) {
+	s, err := new(field.Element).SetBytes(in[:])
+	if err != nil {
+		return nil, err
+	}
+	// Bytes returns the canonical encoding, with the top bit clear, so this
+	// rejects both non-canonical and negative field elements.
+	if string(s.Bytes()) != string(in[:]) || s.IsNegative() == 1 {
+		return nil, errInvalidElement
+	}
+
+	ss := new(field.Element).Square(s)
+	u1 := new(field.Element).Subtract(one, ss)
+	u2 := new(field.Element).Add(one, ss)
+	u2Sqr := new(field.Element).Square(u2)
+
+	// v = -(D * u1^2) - u2_sqr
+	v := new(field.Element).Square(u1)
+	v.Multiply(v, d)
+	v.Negate(v)
+	v.Subtract(v, u2Sqr)
+
+	invSqrt, wasSquare := new(field.Element).SqrtRatio(one, new(field.Element).Multiply(v, u2Sqr))
+
+	denX := new(field.Element).Multiply(invSqrt, u2)
+	denY := new(field.Element).Multiply(invSqrt, denX)
+	denY.Multiply(denY, v)
+
+	x := new(field.Element).Multiply(s, denX)
+	x.Add(x, x)
+	x.Absolute(x)
+	y := new(field.Element).Multiply(u1, denY)
+	t := new(field.Element).Multiply(x, y)
+
+	if wasSquare == 0 || t.IsNegative() == 1 || y.Equal(new(field.Element).Zero()) == 1 {
+		return nil, errInvalidElement
+	}
+	p, err := new(edwards25519.Point).SetExtendedCoordinates(x, y, one, t)
+	if err != nil {
+		return nil, errInvalidElement
+	}
+	return p, nil
+}
+
+// encode encodes an element, following RFC 9496, Section 4.3.2.
+func encode(p *edwards25519.Point) *[Size]byte {
+	x0, y0, z0, t0 := p.ExtendedCoordinates()
+
+	u1 := new(field.Element).Add(z0, y0)
+	u1.Multiply(u1, new(field.Element).Subtract(z0, y0))
+	u2 := new(field.Element).Multiply(x0, y0)
+
+	u2Sqr := new(field.Element).Square(u2)
+	invSqrt, _ := new(field.Element).SqrtRatio(one, u2Sqr.Multiply(u2Sqr, u1))
+
+	den1 := new(field.Element).Multiply(invSqrt, u1)
+	den2 := new(field.Element).Multiply(invSqrt, u2)
+	zInv := new(field.Element).Multiply(den1, den2)
+	zInv.Multiply(zInv, t0)
+
+	ix0 := new(field.Element).Multiply(x0, sqrtM1)
+	iy0 := new(field.Element).Multiply(y0, sqrtM1)
+	enchantedDenominator := new(field.Element).Multiply(den1, invSqrtAMinusD)
+
+	rotate := new(field.Element).Multiply(t0, zInv).IsNegative()
+
+	x := new(field.Element).Select(iy0, x0, rotate)
+	y := new(field.Element).Select(ix0, y0, rotate)
+	z := z0
+	denInv := new(field.Element).Select(enchantedDenominator, den2, rotate)
+
+	yNeg := new(field.Element).Negate(y)
+	y.Select(yNeg, y, new(field.Element).Multiply(x, zInv).IsNegative())
+
+	s := new(field.Element).Subtract(z, y)
+	s.Multiply(s, denInv)
+	s.Absolute(s)
+
+	out := new([Size]byte)
+	copy(out[:], s.Bytes())
+	return out
+}
+
+// mapToPoint is the ristretto255 Elligator map, from RFC 9496, Section
+// 4.3.4.
+func mapToPoint(in []byte) *edwards25519.Point {
+	t, err := new(field.Element).SetBytes(in)
+	if err != nil {
+		panic(err)
+	}
+	minusOne := new(field.Element).Negate(one)
+
+	r := new(field.Element).Square(t)
+	r.Multiply(r, sqrtM1)
+	u := new(field.Element).Add(r, one)
+	u.Multiply(u, oneMinusDSQ)
+	// v = (-1 - r*D) * (r + D)
+	v := new(field.Element).Multiply(r, d)
+	v.Subtract(minusOne, v)
+	v.Multiply(v, new(field.Element).Add(r, d))
+
+	s, wasSquare := new(field.Element).SqrtRatio(u, v)
+	sPrime := new(field.Element).Multiply(s, t)
+	sPrime.Absolute(sPrime)
+	sPrime.Negate(sPrime)
+	s.Select(s, sPrime, wasSquare)
+	c := new(field.Element).Select(minusOne, r, wasSquare)
+
+	// N = c * (r - 1) * D_MINUS_ONE_SQ - v
+	n := new(field.Element).Subtract(r, one)
+	n.Multiply(n, c)
+	n.Multiply(n, dMinusOneSQ)
+	n.Subtract(n, v)
+
+	sSq := new(field.Element).Square(s)
+	w0 := new(field.Element).Multiply(s, v)
+	w0.Add(w0, w0)
+	w1 := new(field.Element).Multiply(n, sqrtADMinusOne)
+	w2 := new(field.Element).Subtract(one, sSq)
+	w3 := new(field.Element).Add(one, sSq)
+
+	p, err := new(edwards25519.Point).SetExtendedCoordinates(
+		new(field.Element).Multiply(w0, w3),
+		new(field.Element).Multiply(w2, w1),
+		new(field.Element).Multiply(w1, w3),
+		new(field.Element).Multiply(w0, w2),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// IsValidPoint reports whether p is the canonical encoding of a ristretto255
+// element.
+func IsValidPoint(p *[Size]byte) bool {
+	_, err := decode(p)
+	return err == nil
+}
+
+// Add returns the sum of the elements p and q. It returns an error if either
+// is not a valid element encoding.
+func Add(p, q *[Size]byte) (*[Size]byte, error) {
+	pp, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+	qq, err := decode(q)
+	if err != nil {
+		return nil, err
+	}
+	return encode(pp.Add(pp, qq)), nil
+}
+
+// Sub returns the difference of the elements p and q. It returns an error if
+// either is not a valid element encoding.
+func Sub(p, q *[Size]byte) (*[Size]byte, error) {
+	pp, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+	qq, err := decode(q)
+	if err != nil {
+		return nil, err
+	}
+	return encode(pp.Subtract(pp, qq)), nil
+}
+
+// FromHash maps 64 bytes, typically the output of a hash function, to an
+// element. If h is uniformly distributed, so is the result, and its discrete
+// logarithm is unknown.
+func FromHash(h *[HashSize]byte) *[Size]byte {
+	p := mapToPoint(h[:32])
+	return encode(p.Add(p, mapToPoint(h[32:])))
+}
+
+// Random returns a uniformly distributed random element. It panics if it
+// cannot read enough random data.
+func Random() *[Size]byte {
+	var h [HashSize]byte
+	randombytes.MustRead(h[:])
+	return FromHash(&h)
+}
+
+// ScalarMult returns n*p. The most significant bit of n is ignored. It
+// returns an error if p is not a valid element encoding, or if the result is
+// the identity element, as it is if n is zero.
+func ScalarMult(n, p *[Size]byte) (*[Size]byte, error) {
+	pp, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+	return checkIdentity(encode(pp.ScalarMult(multScalar(n), pp)))
+}
+
+// ScalarMultBase returns n*B, where B is the ristretto255 generator. The most
+// significant bit of n is ignored. It returns an error if the result is the
+// identity element, as it is if n is zero.
+func ScalarMultBase(n *[ScalarSize]byte) (*[Size]byte, error) {
+	p := new(edwards25519.Point).ScalarBaseMult(multScalar(n))
+	return checkIdentity(encode(p))
+}
+
+// multScalar converts n to a scalar the same way libsodium's
+// crypto_scalarmult_ristretto255 does: the top bit is cleared, and the rest
+// is used unreduced. Reducing it modulo the group order gives the same
+// product, since every element's order divides it.
+func multScalar(n *[ScalarSize]byte) *edwards25519.Scalar {
+	t := *n
+	t[31] &= 127
+	return scalar.New(&t)
+}
+
+func checkIdentity(q *[Size]byte) (*[Size]byte, error) {
+	if *q == [Size]byte{} {
+		return nil, errIdentity
+	}
+	return q, nil
+}
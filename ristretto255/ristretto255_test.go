@@ -0,0 +1,525 @@
+package ristretto255
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeElement(t *testing.T, s string) *[Size]byte {
+	t.Helper()
+	p := new([Size]byte)
+	copy(p[:], decodeHex(t, s))
+	return p
+}
+
+func decodeScalar(t *testing.T, s string) *[ScalarSize]byte {
+	t.Helper()
+	x := new([ScalarSize]byte)
+	copy(x[:], decodeHex(t, s))
+	return x
+}
+
+// Test vectors were generated with libsodium 1.0.18 unless noted otherwise.
+
+// multiplesOfGenerator are the encodings of 0*B through 15*B, which also
+// appear in RFC 9496, Appendix A.1.
+var multiplesOfGenerator = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+	"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+	"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+	"da80862773358b466ffadfe0b3293ab3d9fd53c5ea6c955358f568322daf6a57",
+	"e882b131016b52c1d3337080187cf768423efccbb517bb495ab812c4160ff44e",
+	"f64746d3c92b13050ed8d80236a7f0007c3b3f962f5ba793d19a601ebb1df403",
+	"44f53520926ec81fbd5a387845beb7df85a96a24ece18738bdcfa6a7822a176d",
+	"903293d8f2287ebe10e2374dc1a53e0bc887e592699f02d077d5263cdd55601c",
+	"02622ace8f7303a31cafc63f8fc48fdc16e1c8c8d234b2f0d6685282a9076031",
+	"20706fd788b2720a1ed2a5dad4952b01f413bcf0e7564de8cdc816689e2db95f",
+	"bce83f8ba5dd2fa572864c24ba1810f9522bc6004afe95877ac73241cafdab42",
+	"e4549ee16b9aa03099ca208c67adafcafa4c3f3e4e5303de6026e3ca8ff84460",
+	"aa52e000df2e16f55fb1032fc33bc42742dad6bd5a8fc0be0167436c5948501f",
+	"46376b80f409b29dc2b5f6f0c52591990896e5716f41477cd30085ab7f10301e",
+	"e0c418f7c8d9c4cdd7395b93ea124f3ad99021bb681dfc3302a9d99a2e53e64e",
+}
+
+func TestScalarMultBase(t *testing.T) {
+	for i, want := range multiplesOfGenerator {
+		var n [ScalarSize]byte
+		n[0] = byte(i)
+		q, err := ScalarMultBase(&n)
+		if i == 0 {
+			if err == nil {
+				t.Errorf("0*B: expected error, got %x", q)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d*B: %v", i, err)
+		}
+		if got := hex.EncodeToString(q[:]); got != want {
+			t.Errorf("%d*B: got %s, want %s", i, got, want)
+		}
+		if !IsValidPoint(q) {
+			t.Errorf("%d*B is not a valid point", i)
+		}
+	}
+}
+
+// invalidElements are from RFC 9496, Appendix A.2.
+var invalidElements = []string{
+	// non-canonical field encodings
+	"00ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"f3ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	// negative field elements
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"01ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"ed57ffd8c914fb201471d1c3d245ce3c746fcbe63a3679d51b6a516ebebe0e20",
+	// non-square x^2
+	"26948d35ca62e643e26a83177332e6b6afeb9d08e4268b650f1f5bbd8d81d371",
+	"4eac077a713c57b4f4397629a4145982c661f48044dd3f96427d40b147d9742f",
+	// negative xy value
+	"3eb858e78f5a7254d8c9731174a94f76755fd3941c0ac93735c07ba14579630e",
+	// s = -1, which causes y = 0
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+}
+
+func TestInvalidElements(t *testing.T) {
+	valid := decodeElement(t, multiplesOfGenerator[1])
+	var n [ScalarSize]byte
+	n[0] = 1
+	for _, s := range invalidElements {
+		p := decodeElement(t, s)
+		if IsValidPoint(p) {
+			t.Errorf("IsValidPoint accepted %s", s)
+		}
+		if _, err := Add(p, valid); err == nil {
+			t.Errorf("Add accepted %s", s)
+		}
+		if _, err := Sub(valid, p); err == nil {
+			t.Errorf("Sub accepted %s", s)
+		}
+		if _, err := ScalarMult(&n, p); err == nil {
+			t.Errorf("ScalarMult accepted %s", s)
+		}
+	}
+	if !IsValidPoint(decodeElement(t, multiplesOfGenerator[0])) {
+		t.Errorf("IsValidPoint rejected the identity element")
+	}
+}
+
+var fromHashTests = []struct {
+	hash, element string
+}{
+	{
+		"00070e151c232a31383f464d545b626970777e858c939aa1a8afb6bdc4cbd2d9e0e7eef5fc030a11181f262d343b424950575e656c737a81888f969da4abb2b9",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+	},
+	{
+		"1f262d343b424950575e656c737a81888f969da4abb2b9c0c7ced5dce3eaf1f8ff060d141b222930373e454c535a61686f767d848b9299a0a7aeb5bcc3cad1d8",
+		"d6f8ab5d521c53f49877d79f49cbb0176d159c355684dc843d748904e287c813",
+	},
+	{
+		"3e454c535a61686f767d848b9299a0a7aeb5bcc3cad1d8dfe6edf4fb020910171e252c333a41484f565d646b727980878e959ca3aab1b8bfc6cdd4dbe2e9f0f7",
+		"3e8392307d7bde0b8a8fc0dab45c4de1c2b267cb36bd1810205a0f4b1b90bf49",
+	},
+	{
+		"5d646b727980878e959ca3aab1b8bfc6cdd4dbe2e9f0f7fe050c131a21282f363d444b525960676e757c838a91989fa6adb4bbc2c9d0d7dee5ecf3fa01080f16",
+		"c075975865de3ca2d0f041f8a04b7f513232bd60d65eebf9359b0fe0132eb84d",
+	},
+}
+
+func TestFromHash(t *testing.T) {
+	for i, tt := range fromHashTests {
+		var h [HashSize]byte
+		copy(h[:], decodeHex(t, tt.hash))
+		if got := hex.EncodeToString(FromHash(&h)[:]); got != tt.element {
+			t.Errorf("%d: got %s, want %s", i, got, tt.element)
+		}
+	}
+}
+
+var addSubTests = []struct {
+	p, q, sum, difference string
+}{
+	{
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+		"d6f8ab5d521c53f49877d79f49cbb0176d159c355684dc843d748904e287c813",
+		"b22d73ad665052391ae14e657585c788d368bb11d9d198638101f80259911d4c",
+		"480d0026e137a70a2453b98d8cc2fc4e693ebc9373dfa32799fb8c737776af30",
+	},
+	{
+		"d6f8ab5d521c53f49877d79f49cbb0176d159c355684dc843d748904e287c813",
+		"3e8392307d7bde0b8a8fc0dab45c4de1c2b267cb36bd1810205a0f4b1b90bf49",
+		"886e95d1cd1ba81b86f7643ae5f5b4891bc2dfb448dfd6dc454c4642ff8cb448",
+		"18df7ea22cdfa845c4fbec08c3ab111a39eb811a196d85a627aa3b5b1cd6800d",
+	},
+	{
+		"3e8392307d7bde0b8a8fc0dab45c4de1c2b267cb36bd1810205a0f4b1b90bf49",
+		"c075975865de3ca2d0f041f8a04b7f513232bd60d65eebf9359b0fe0132eb84d",
+		"964b252c6384739733459cfac14f36b3cc8110de9e1b5443e381d1c942f6ff4a",
+		"0415c01c5153f5902b1c662979a0f42c31e4a94796c0746000051d5e8a8e4350",
+	},
+}
+
+func TestAddSub(t *testing.T) {
+	for i, tt := range addSubTests {
+		p, q := decodeElement(t, tt.p), decodeElement(t, tt.q)
+		sum, err := Add(p, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(sum[:]); got != tt.sum {
+			t.Errorf("%d: Add: got %s, want %s", i, got, tt.sum)
+		}
+		difference, err := Sub(p, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(difference[:]); got != tt.difference {
+			t.Errorf("%d: Sub: got %s, want %s", i, got, tt.difference)
+		}
+		back, err := Add(difference, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *back != *p {
+			t.Errorf("%d: (p - q) + q != p", i)
+		}
+	}
+}
+
+var scalarMultTests = []struct {
+	n, p, q string
+}{
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+		"863d571b5d8d93864a64ed5afe1200251d5a03ca536eda1fd56256aa83dffa57",
+	},
+	{
+		"05121f2c394653606d7a8794a1aebbc8d5e2effc091623303d4a5764717e8b98",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+		"b02e84e782b39cfb21a3ad743d7d3c221f948be27bc37c27cb0015a3a7f42843",
+	},
+	{
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"b0f13fd3773accfbe6d6116581c712b0d24572beb3f0ca264acd61cd904c8c45",
+		"",
+	},
+}
+
+func TestScalarMult(t *testing.T) {
+	for i, tt := range scalarMultTests {
+		q, err := ScalarMult(decodeScalar(t, tt.n), decodeElement(t, tt.p))
+		if tt.q == "" {
+			if err == nil {
+				t.Errorf("%d: expected error, got %x", i, q)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if got := hex.EncodeToString(q[:]); got != tt.q {
+			t.Errorf("%d: got %s, want %s", i, got, tt.q)
+		}
+	}
+
+	// n*B computed both ways must agree.
+	n := ScalarRandom()
+	q1, err := ScalarMultBase(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q2, err := ScalarMult(n, decodeElement(t, multiplesOfGenerator[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *q1 != *q2 {
+		t.Errorf("ScalarMultBase and ScalarMult disagree")
+	}
+}
+
+var scalarBinaryTests = []struct {
+	x, y, sum, difference, product string
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"41b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"4bd40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a4ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ad2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"42b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+	},
+	{
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ebd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"49d40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a2ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+	},
+	{
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ab2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"40b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+		"41b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+	},
+	{
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"49d40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"4bd40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+	},
+	{
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a7d42103904465560ffbdeee35836a5ef0842060c0c8ef704d22fdcde207650c",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"b00f9f1f3c629267d874f9f4548f0611fd59ba60f72809ee8a510d5eb444510d",
+	},
+	{
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"092197230a33a326524025618e1ee75517bcac67671b9f1172999158195c2a02",
+		"9eb38adf8511c22fbdbab98da7648308d9c873f858ad505fdb886b75c9ab3a0a",
+		"7e49038f5639ddfefc8ded4f2ea2ad9d3dd624417719af5bd6a95a680e63400a",
+	},
+	{
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"ab2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ad2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"41b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+	},
+	{
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"092197230a33a326524025618e1ee75517bcac67671b9f1172999158195c2a02",
+		"4f206b7d9451502819e23d1537955b0c27378c07a752afa02477948a3654c505",
+		"7e49038f5639ddfefc8ded4f2ea2ad9d3dd624417719af5bd6a95a680e63400a",
+	},
+	{
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"584102a19e84f34e6b226376c5b342623ef3386f0e6e4eb2961026e34fb0ef07",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"d627a91b38e51fccab53f0de6ced4ad8bb2b6b867dc058bc949ccebd3286b00d",
+	},
+	// Inputs that are not reduced modulo L. These were computed with Python's
+	// arbitrary-precision integers.
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"7995ae602fa215568d7e7119a020b5eb7642103060e477b82691fe66f183320e",
+		"d2c08c5d9f5db0ff7d83922a6a9d4a8d86bdefcf9f1b8847d96e01990e7ccd01",
+		"0fa1e2cb0473db362261dd050500675eac142e5906af92c0b29cdc72c2b31a0d",
+	},
+}
+
+func TestScalarBinary(t *testing.T) {
+	for i, tt := range scalarBinaryTests {
+		x, y := decodeScalar(t, tt.x), decodeScalar(t, tt.y)
+		if got := hex.EncodeToString(ScalarAdd(x, y)[:]); got != tt.sum {
+			t.Errorf("%d: ScalarAdd: got %s, want %s", i, got, tt.sum)
+		}
+		if got := hex.EncodeToString(ScalarSub(x, y)[:]); got != tt.difference {
+			t.Errorf("%d: ScalarSub: got %s, want %s", i, got, tt.difference)
+		}
+		if got := hex.EncodeToString(ScalarMul(x, y)[:]); got != tt.product {
+			t.Errorf("%d: ScalarMul: got %s, want %s", i, got, tt.product)
+		}
+	}
+}
+
+var scalarUnaryTests = []struct {
+	s, negation, complement, inverse string
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+	},
+	{
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+		"a3ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"a4ffe92c458fd680e3500c5a543b3adb87bdefcf9f1b8847d96e01990e7ccd01",
+		"522f982d76cbdee2ce448f81bf5c54b0c48c4863b4759cfef0aa6e8028e9770e",
+	},
+	{
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+		"41b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+		"42b3740ccba098b0a00bc6e7fb9fbde3608663c8f8c8d8a6b4f76c0ed827080c",
+		"1b89601a216e1f2c549a586a501faa8bbad35e216bb4912b0e3d2b5965f0380a",
+	},
+	// Not reduced modulo L; computed with Python.
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"d13e5dcfa531268165cd792fea9def4d01000000000000000000000000000000",
+		"d23e5dcfa531268165cd792fea9def4d01000000000000000000000000000000",
+		"d661d1ae29161c6072d1fe207335ba6d038b343a7321c7cdd13987faed57f00b",
+	},
+	// L itself. libsodium's crypto_core_ristretto255_scalar_invert returns
+	// zero for it, and only fails on the all-zero string.
+	{
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"",
+	},
+}
+
+func TestScalarUnary(t *testing.T) {
+	for i, tt := range scalarUnaryTests {
+		s := decodeScalar(t, tt.s)
+		if got := hex.EncodeToString(ScalarNegate(s)[:]); got != tt.negation {
+			t.Errorf("%d: ScalarNegate: got %s, want %s", i, got, tt.negation)
+		}
+		if got := hex.EncodeToString(ScalarComplement(s)[:]); got != tt.complement {
+			t.Errorf("%d: ScalarComplement: got %s, want %s", i, got, tt.complement)
+		}
+		inverse, err := ScalarInvert(s)
+		if tt.inverse == "" {
+			if err == nil {
+				t.Errorf("%d: ScalarInvert: expected error, got %x", i, inverse)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: ScalarInvert: %v", i, err)
+		}
+		if got := hex.EncodeToString(inverse[:]); got != tt.inverse {
+			t.Errorf("%d: ScalarInvert: got %s, want %s", i, got, tt.inverse)
+		}
+	}
+}
+
+var scalarReduceTests = []struct {
+	in, out string
+}{
+	{
+		"00070e151c232a31383f464d545b626970777e858c939aa1a8afb6bdc4cbd2d9e0e7eef5fc030a11181f262d343b424950575e656c737a81888f969da4abb2b9",
+		"4ad40b30d5d33bd7f24beb488abea4397842103060e477b82691fe66f183320e",
+	},
+	{
+		"1f262d343b424950575e656c737a81888f969da4abb2b9c0c7ced5dce3eaf1f8ff060d141b222930373e454c535a61686f767d848b9299a0a7aeb5bcc3cad1d8",
+		"ac2081504fc279a7359131bbe25921319f799c37073727594b0893f127d8f703",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"000f9c44e31106a447938568a71b0ed065bef517d273ecce3d9a307c1b419903",
+	},
+}
+
+func TestScalarReduce(t *testing.T) {
+	for i, tt := range scalarReduceTests {
+		var in [NonReducedScalarSize]byte
+		copy(in[:], decodeHex(t, tt.in))
+		if got := hex.EncodeToString(ScalarReduce(&in)[:]); got != tt.out {
+			t.Errorf("%d: got %s, want %s", i, got, tt.out)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	p, q := Random(), Random()
+	if !IsValidPoint(p) || !IsValidPoint(q) {
+		t.Fatalf("Random returned an invalid point")
+	}
+	if *p == *q {
+		t.Errorf("Random returned the same point twice")
+	}
+	var zero [ScalarSize]byte
+	s := ScalarRandom()
+	if *s == zero {
+		t.Errorf("ScalarRandom returned zero")
+	}
+	if *ScalarReduce(new([NonReducedScalarSize]byte)) != zero {
+		t.Errorf("ScalarReduce(0) is not zero")
+	}
+	inverse, err := ScalarInvert(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := [ScalarSize]byte{0: 1}
+	if *ScalarMul(s, inverse) != one {
+		t.Errorf("s * s^-1 != 1")
+	}
+}
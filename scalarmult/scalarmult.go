@@ -23,7 +23,11 @@
 // scalarmult is compatible with NaCL: https://nacl.cr.yp.to/scalarmult.html
 package scalarmult
 
-import "golang.org/x/crypto/curve25519"
+import (
+	"errors"
+
+	"golang.org/x/crypto/curve25519"
+)
 
 // Size is the size, in bytes, of a value for use in scalar multiplication
 const Size = 32
@@ -45,3 +49,19 @@ func Mult(in, base *[Size]byte) *[Size]byte {
 	curve25519.ScalarMult(key, in, base)
 	return key
 }
+
+var errLowOrder = errors.New("scalarmult: low order point")
+
+// MultChecked is like Mult, but returns an error if the result is all zeros,
+// which happens when base is a point of small order. Protocols that need each
+// party to contribute to the shared secret should use MultChecked, as
+// libsodium's crypto_scalarmult does.
+func MultChecked(in, base *[Size]byte) (*[Size]byte, error) {
+	out, err := curve25519.X25519(in[:], base[:])
+	if err != nil {
+		return nil, errLowOrder
+	}
+	key := new([Size]byte)
+	copy(key[:], out)
+	return key, nil
+}
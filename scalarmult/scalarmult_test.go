@@ -1,6 +1,9 @@
 package scalarmult
 
-import "testing"
+import (
+	"encoding/hex"
+	"testing"
+)
 
 func Test1(t *testing.T) {
 	secret := &[32]byte{
@@ -90,3 +93,46 @@ func Test6(t *testing.T) {
 		t.Errorf("Mult: got %v, want %v", key, want)
 	}
 }
+
+// lowOrderPoints are the encodings of points of small order on Curve25519,
+// from libsodium's blocklist.
+var lowOrderPoints = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"e0eb7a7c3b41b8ae1656e3faf19fc46ada098deb9c32b1fd866205165f49b800",
+	"5f9c95bca3508c24b1d0b1559c83ef5b04445cc4581c8e86d8224eddd09f1157",
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+}
+
+func TestMultChecked(t *testing.T) {
+	secret := &[32]byte{
+		0x77, 0x07, 0x6d, 0x0a, 0x73, 0x18, 0xa5, 0x7d,
+		0x3c, 0x16, 0xc1, 0x72, 0x51, 0xb2, 0x66, 0x45,
+		0xdf, 0x4c, 0x2f, 0x87, 0xeb, 0xc0, 0x99, 0x2a,
+		0xb1, 0x77, 0xfb, 0xa5, 0x1d, 0xb9, 0x2c, 0x2a,
+	}
+	public := &[32]byte{
+		0xde, 0x9e, 0xdb, 0x7d, 0x7b, 0x7d, 0xc1, 0xb4,
+		0xd3, 0x5b, 0x61, 0xc2, 0xec, 0xe4, 0x35, 0x37,
+		0x3f, 0x83, 0x43, 0xc8, 0x5b, 0x78, 0x67, 0x4d,
+		0xad, 0xfc, 0x7e, 0x14, 0x6f, 0x88, 0x2b, 0x4f,
+	}
+	key, err := MultChecked(secret, public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Mult(secret, public); *key != *want {
+		t.Errorf("MultChecked: got %x, want %x", key, want)
+	}
+
+	for _, s := range lowOrderPoints {
+		var point [Size]byte
+		b, _ := hex.DecodeString(s)
+		copy(point[:], b)
+		if key, err := MultChecked(secret, &point); err == nil {
+			t.Errorf("MultChecked(%s): expected error, got %x", s, key)
+		}
+	}
+}
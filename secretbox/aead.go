@@ -0,0 +1,72 @@
+package secretbox
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/internal/subtle"
+	"github.com/kevinburke/nacl/onetimeauth"
+)
+
+type aead struct {
+	key [nacl.KeySize]byte
+}
+
+// NewAEAD returns a cipher.AEAD that encrypts and authenticates messages with
+// XSalsa20 and Poly1305 under key. The nonce size is nacl.NonceSize and the
+// overhead is Overhead.
+//
+// If the additional data passed to Seal is empty, the output is identical to
+// the output of the Seal function in this package, and Open accepts anything
+// produced by Seal. Otherwise the Poly1305 authenticator covers the additional
+// data, padded to a multiple of 16 bytes, then the ciphertext, padded to a
+// multiple of 16 bytes, then the length of each as a 64-bit little-endian
+// integer; this is the same layout RFC 8439 uses for ChaCha20-Poly1305. As with
+// the Seal function, the authenticator is placed before the ciphertext.
+func NewAEAD(key nacl.Key) cipher.AEAD {
+	a := new(aead)
+	copy(a.key[:], key[:])
+	return a
+}
+
+func (a *aead) NonceSize() int {
+	return nacl.NonceSize
+}
+
+func (a *aead) Overhead() int {
+	return Overhead
+}
+
+func (a *aead) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != nacl.NonceSize {
+		panic("secretbox: incorrect nonce length given to AEAD")
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+Overhead)
+	if subtle.InexactOverlap(out[Overhead:], plaintext) {
+		// The authenticator comes first, so encrypting plaintext in place
+		// shifts it; work from a copy instead.
+		plaintext = append([]byte(nil), plaintext...)
+	}
+	tag := sealDetached(out[Overhead:], plaintext, nacl.Nonce(nonce), &a.key, additionalData)
+	copy(out, tag[:])
+	return ret
+}
+
+var errOpen = errors.New("secretbox: message authentication failed")
+
+func (a *aead) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != nacl.NonceSize {
+		panic("secretbox: incorrect nonce length given to AEAD")
+	}
+	if len(ciphertext) < Overhead {
+		return nil, errOpen
+	}
+	var tag [onetimeauth.Size]byte
+	copy(tag[:], ciphertext)
+	ret, ok := openDetached(dst, ciphertext[Overhead:], &tag, nacl.Nonce(nonce), &a.key, additionalData)
+	if !ok {
+		return nil, errOpen
+	}
+	return ret, nil
+}
@@ -0,0 +1,125 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+func TestAEADMatchesSeal(t *testing.T) {
+	key := nacl.NewKey()
+	nonce := nacl.NewNonce()
+	a := NewAEAD(key)
+	if a.NonceSize() != nacl.NonceSize || a.Overhead() != Overhead {
+		t.Fatalf("got NonceSize %d, Overhead %d", a.NonceSize(), a.Overhead())
+	}
+	for msgLen := 0; msgLen < 128; msgLen += 17 {
+		message := make([]byte, msgLen)
+		randombytes.MustRead(message)
+		box := a.Seal(nil, nonce[:], message, nil)
+		if want := Seal(nil, message, nonce, key); !bytes.Equal(box, want) {
+			t.Errorf("%d: AEAD Seal with no additional data does not match Seal", msgLen)
+		}
+		opened, err := a.Open(nil, nonce[:], box, nil)
+		if err != nil {
+			t.Errorf("%d: failed to open box: %v", msgLen, err)
+			continue
+		}
+		if !bytes.Equal(opened, message) {
+			t.Errorf("%d: got %x, want %x", msgLen, opened, message)
+		}
+	}
+}
+
+func TestAEADAdditionalData(t *testing.T) {
+	var key [32]byte
+	var nonce [24]byte
+	for i := range key {
+		key[i] = 1
+	}
+	for i := range nonce {
+		nonce[i] = 2
+	}
+	message := bytes.Repeat([]byte{3}, 40)
+	ad := []byte("header")
+
+	a := NewAEAD(&key)
+	box := a.Seal(nil, nonce[:], message, ad)
+	// expected was generated from libsodium's crypto_stream_xsalsa20 and
+	// crypto_onetimeauth, following the layout documented on NewAEAD.
+	expected, _ := hex.DecodeString("6f75e529e799f470683cb21a06ab0a9cfe66ddfe7d39d14e637eb4fd5b45beadab55198df6ab5368439792a23c87db70acb6156dc5ef957a")
+	if !bytes.Equal(box, expected) {
+		t.Fatalf("box didn't match, got\n%x\n, expected\n%x", box, expected)
+	}
+
+	opened, err := a.Open(nil, nonce[:], box, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("got %x, want %x", opened, message)
+	}
+	if _, err := a.Open(nil, nonce[:], box, nil); err == nil {
+		t.Errorf("opened box without additional data")
+	}
+	if _, err := a.Open(nil, nonce[:], box, []byte("headex")); err == nil {
+		t.Errorf("opened box with the wrong additional data")
+	}
+	if _, ok := Open(nil, box, &nonce, &key); ok {
+		t.Errorf("Open accepted box sealed with additional data")
+	}
+	for i := range box {
+		box[i] ^= 0x20
+		if _, err := a.Open(nil, nonce[:], box, ad); err == nil {
+			t.Errorf("box was opened after corrupting byte %d", i)
+		}
+		box[i] ^= 0x20
+	}
+}
+
+func TestAEADInPlace(t *testing.T) {
+	key := nacl.NewKey()
+	nonce := nacl.NewNonce()
+	a := NewAEAD(key)
+	message := make([]byte, 100)
+	randombytes.MustRead(message)
+	ad := []byte("additional data")
+
+	want := a.Seal(nil, nonce[:], message, ad)
+	buf := make([]byte, len(message), len(message)+Overhead)
+	copy(buf, message)
+	box := a.Seal(buf[:0], nonce[:], buf, ad)
+	if !bytes.Equal(box, want) {
+		t.Fatalf("in-place Seal: got %x, want %x", box, want)
+	}
+	opened, err := a.Open(box[:0], nonce[:], box, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Fatalf("in-place Open: got %x, want %x", opened, message)
+	}
+}
+
+func TestOpenForged(t *testing.T) {
+	key := nacl.NewKey()
+	nonce := nacl.NewNonce()
+	box := Seal(nil, []byte("forged message"), nonce, key)
+	box[0] ^= 1
+
+	// Nothing may be written to the spare capacity of out when the box
+	// doesn't authenticate.
+	out := make([]byte, 1, 64)
+	if _, ok := Open(out, box, nonce, key); ok {
+		t.Fatal("Open accepted a forged box")
+	}
+	if _, err := NewAEAD(key).Open(out, nonce[:], box, nil); err != errOpen {
+		t.Errorf("AEAD Open: got error %v, want %v", err, errOpen)
+	}
+	if !bytes.Equal(out[:cap(out)], make([]byte, cap(out))) {
+		t.Errorf("out was written to: %x", out[:cap(out)])
+	}
+}
@@ -5,7 +5,9 @@
 package secretbox_test
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/kevinburke/nacl"
 	"github.com/kevinburke/nacl/secretbox"
@@ -39,3 +41,29 @@ func Example() {
 	fmt.Println(string(decrypted))
 	// Output: hello world
 }
+
+func ExampleNewWriter() {
+	key, err := nacl.Load("6368616e676520746869732070617373776f726420746f206120736563726574")
+	if err != nil {
+		panic(err)
+	}
+
+	// Encrypt a stream of data without holding all of it in memory. Close
+	// must be called to write the final chunk.
+	var encrypted bytes.Buffer
+	w := secretbox.NewWriter(&encrypted, key)
+	if _, err := io.WriteString(w, "hello world"); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+
+	r := secretbox.NewReader(&encrypted, key)
+	decrypted, err := io.ReadAll(r)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(decrypted))
+	// Output: hello world
+}
@@ -28,13 +28,14 @@ might present some implementations with no other choice.
 
 Thus large amounts of data should be chunked so that each message is small.
 (Each message still needs a unique nonce.) If in doubt, 16KB is a reasonable
-chunk size.
+chunk size. NewWriter and NewReader implement one such chunking scheme.
 
 This package is interoperable with NaCl: https://nacl.cr.yp.to/secretbox.html.
 */
 package secretbox // import "github.com/kevinburke/nacl/secretbox"
 
 import (
+	"encoding/binary"
 	"errors"
 
 	"github.com/kevinburke/nacl"
@@ -74,46 +75,119 @@ func EasySeal(message []byte, key nacl.Key) []byte {
 // must not overlap message. The key and nonce pair must be unique for each
 // distinct message and the output will be Overhead bytes longer than message.
 func Seal(out, message []byte, nonce nacl.Nonce, key nacl.Key) []byte {
-	subKey, counter := nacl.Setup(nonce, key)
+	ret, out := sliceForAppend(out, len(message)+onetimeauth.Size)
+	if subtle.AnyOverlap(out, message) {
+		panic("nacl: invalid buffer overlap")
+	}
+	tag := sealDetached(out[onetimeauth.Size:], message, nonce, key, nil)
+	copy(out, tag[:])
+	return ret
+}
+
+// SealDetached encrypts message and appends the ciphertext to out, which must
+// not overlap message. Unlike Seal, the authenticator is returned separately
+// rather than being prepended to the ciphertext, so the appended ciphertext is
+// the same length as message. The key and nonce pair must be unique for each
+// distinct message.
+func SealDetached(out, message []byte, nonce nacl.Nonce, key nacl.Key) ([]byte, *[onetimeauth.Size]byte) {
+	ret, out := sliceForAppend(out, len(message))
+	if subtle.AnyOverlap(out, message) {
+		panic("nacl: invalid buffer overlap")
+	}
+	tag := sealDetached(out, message, nonce, key, nil)
+	return ret, tag
+}
+
+// keyStream returns the Poly1305 key for nonce and key, along with the state
+// needed to continue the XSalsa20 keystream.
+func keyStream(nonce nacl.Nonce, key nacl.Key) (poly1305Key *[32]byte, firstBlock *[64]byte, subKey nacl.Key, counter *[16]byte) {
+	subKey, counter = nacl.Setup(nonce, key)
 
 	// The Poly1305 key is generated by encrypting 32 bytes of zeros. Since
 	// Salsa20 works with 64-byte blocks, we also generate 32 bytes of
 	// keystream as a side effect.
-	var firstBlock [64]byte
+	firstBlock = new([64]byte)
 	salsa.XORKeyStream(firstBlock[:], firstBlock[:], counter, subKey)
 
-	var poly1305Key [32]byte
+	poly1305Key = new([32]byte)
 	copy(poly1305Key[:], firstBlock[:])
+	return poly1305Key, firstBlock, subKey, counter
+}
 
-	ret, out := sliceForAppend(out, len(message)+onetimeauth.Size)
-	if subtle.AnyOverlap(out, message) {
-		panic("nacl: invalid buffer overlap")
-	}
-
-	// We XOR up to 32 bytes of message with the keystream generated from
-	// the first block.
-	firstMessageBlock := message
+// xorKeyStream XORs in with the keystream that follows the Poly1305 key and
+// writes the result to out, which must be at least as long as in.
+func xorKeyStream(out, in []byte, firstBlock *[64]byte, subKey nacl.Key, counter *[16]byte) {
+	// We XOR up to 32 bytes of in with the keystream generated from the
+	// first block.
+	firstMessageBlock := in
 	if len(firstMessageBlock) > 32 {
 		firstMessageBlock = firstMessageBlock[:32]
 	}
-
-	tagOut := out
-	out = out[onetimeauth.Size:]
 	for i, x := range firstMessageBlock {
 		out[i] = firstBlock[32+i] ^ x
 	}
-	message = message[len(firstMessageBlock):]
-	ciphertext := out
+	in = in[len(firstMessageBlock):]
 	out = out[len(firstMessageBlock):]
 
-	// Now encrypt the rest.
+	// Now process the rest.
 	counter[8] = 1
-	salsa.XORKeyStream(out, message, counter, subKey)
+	salsa.XORKeyStream(out, in, counter, subKey)
+}
 
-	tag := onetimeauth.Sum(ciphertext, &poly1305Key)
-	copy(tagOut, tag[:])
+var pad0 [16]byte
+
+// authenticate returns the Poly1305 authenticator for ciphertext and
+// additionalData under the one-time key poly1305Key.
+//
+// If additionalData is empty, the authenticator covers the ciphertext alone,
+// exactly as in NaCl. Otherwise it covers, as in RFC 8439,
+//
+//	additionalData || pad16(additionalData) || ciphertext || pad16(ciphertext) ||
+//	    uint64_le(len(additionalData)) || uint64_le(len(ciphertext))
+//
+// where pad16(x) is the zero bytes needed to pad x to a multiple of 16 bytes.
+func authenticate(ciphertext, additionalData []byte, poly1305Key *[32]byte) *[onetimeauth.Size]byte {
+	if len(additionalData) == 0 {
+		return onetimeauth.Sum(ciphertext, poly1305Key)
+	}
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(ciphertext)))
+	mac := onetimeauth.New(poly1305Key)
+	mac.Write(additionalData)
+	mac.Write(pad0[:(16-len(additionalData)%16)%16])
+	mac.Write(ciphertext)
+	mac.Write(pad0[:(16-len(ciphertext)%16)%16])
+	mac.Write(lengths[:])
+	tag := new([onetimeauth.Size]byte)
+	mac.Sum(tag[:0])
+	return tag
+}
 
-	return ret
+// sealDetached encrypts message into out, which must be exactly as long as
+// message, and returns the authenticator for the ciphertext and
+// additionalData.
+func sealDetached(out, message []byte, nonce nacl.Nonce, key nacl.Key, additionalData []byte) *[onetimeauth.Size]byte {
+	poly1305Key, firstBlock, subKey, counter := keyStream(nonce, key)
+	xorKeyStream(out, message, firstBlock, subKey, counter)
+	return authenticate(out, additionalData, poly1305Key)
+}
+
+// openDetached checks that tag authenticates ciphertext and additionalData
+// and, if so, decrypts ciphertext and appends the result to dst. Nothing is
+// allocated or written if authentication fails.
+func openDetached(dst, ciphertext []byte, tag *[onetimeauth.Size]byte, nonce nacl.Nonce, key nacl.Key, additionalData []byte) ([]byte, bool) {
+	poly1305Key, firstBlock, subKey, counter := keyStream(nonce, key)
+	expected := authenticate(ciphertext, additionalData, poly1305Key)
+	if !nacl.Verify16(tag, expected) {
+		return nil, false
+	}
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	if subtle.InexactOverlap(out, ciphertext) {
+		ciphertext = append([]byte(nil), ciphertext...)
+	}
+	xorKeyStream(out, ciphertext, firstBlock, subKey, counter)
+	return ret, true
 }
 
 var errInvalidInput = errors.New("secretbox: Could not decrypt invalid input")
@@ -142,42 +216,15 @@ func Open(out, box []byte, nonce nacl.Nonce, key nacl.Key) ([]byte, bool) {
 		return nil, false
 	}
 
-	subKey, counter := nacl.Setup(nonce, key)
-
-	// The Poly1305 key is generated by encrypting 32 bytes of zeros. Since
-	// Salsa20 works with 64-byte blocks, we also generate 32 bytes of
-	// keystream as a side effect.
-	var firstBlock [64]byte
-	salsa.XORKeyStream(firstBlock[:], firstBlock[:], counter, subKey)
-
-	var poly1305Key [32]byte
-	copy(poly1305Key[:], firstBlock[:])
 	var tag [onetimeauth.Size]byte
 	copy(tag[:], box)
 
-	if !onetimeauth.Verify(&tag, box[onetimeauth.Size:], &poly1305Key) {
-		return nil, false
-	}
-
-	ret, out := sliceForAppend(out, len(box)-Overhead)
-
-	// We XOR up to 32 bytes of box with the keystream generated from
-	// the first block.
-	box = box[Overhead:]
-	firstMessageBlock := box
-	if len(firstMessageBlock) > 32 {
-		firstMessageBlock = firstMessageBlock[:32]
-	}
-	for i, x := range firstMessageBlock {
-		out[i] = firstBlock[32+i] ^ x
-	}
-
-	box = box[len(firstMessageBlock):]
-	out = out[len(firstMessageBlock):]
-
-	// Now decrypt the rest.
-	counter[8] = 1
-	salsa.XORKeyStream(out, box, counter, subKey)
+	return openDetached(out, box[Overhead:], &tag, nonce, key, nil)
+}
 
-	return ret, true
+// OpenDetached authenticates and decrypts a ciphertext and authenticator
+// produced by SealDetached and appends the message to out, which must not
+// overlap ciphertext. The output will be the same length as ciphertext.
+func OpenDetached(out, ciphertext []byte, tag *[onetimeauth.Size]byte, nonce nacl.Nonce, key nacl.Key) ([]byte, bool) {
+	return openDetached(out, ciphertext, tag, nonce, key, nil)
 }
@@ -184,3 +184,51 @@ func BenchmarkOpen1K(b *testing.B) {
 func BenchmarkOpen8K(b *testing.B) {
 	benchmarkOpenSize(b, 8192)
 }
+
+func TestSealOpenDetached(t *testing.T) {
+	var key [32]byte
+	var nonce [24]byte
+	var message [64]byte
+	for i := range key[:] {
+		key[i] = 1
+	}
+	for i := range nonce[:] {
+		nonce[i] = 2
+	}
+	for i := range message[:] {
+		message[i] = 3
+	}
+
+	ciphertext, tag := SealDetached(nil, message[:], &nonce, &key)
+	// expected was generated using libsodium's crypto_secretbox_detached.
+	expectedTag, _ := hex.DecodeString("8442bc313f4626f1359e3b50122b6ce6")
+	expected, _ := hex.DecodeString("fe66ddfe7d39d14e637eb4fd5b45beadab55198df6ab5368439792a23c87db70acb6156dc5ef957ac04f6276cf6093b84be77ff0849cc33e34b7254d5a8f65ad")
+	if !bytes.Equal(tag[:], expectedTag) {
+		t.Errorf("tag didn't match, got\n%x\n, expected\n%x", tag, expectedTag)
+	}
+	if !bytes.Equal(ciphertext, expected) {
+		t.Fatalf("ciphertext didn't match, got\n%x\n, expected\n%x", ciphertext, expected)
+	}
+
+	opened, ok := OpenDetached(nil, ciphertext, tag, &nonce, &key)
+	if !ok {
+		t.Fatalf("failed to open detached box")
+	}
+	if !bytes.Equal(opened, message[:]) {
+		t.Fatalf("got %x, expected %x", opened, message)
+	}
+	for i := range ciphertext {
+		ciphertext[i] ^= 0x20
+		if _, ok := OpenDetached(nil, ciphertext, tag, &nonce, &key); ok {
+			t.Errorf("box was opened after corrupting byte %d", i)
+		}
+		ciphertext[i] ^= 0x20
+	}
+	for i := range tag {
+		tag[i] ^= 0x20
+		if _, ok := OpenDetached(nil, ciphertext, tag, &nonce, &key); ok {
+			t.Errorf("box was opened after corrupting tag byte %d", i)
+		}
+		tag[i] ^= 0x20
+	}
+}
@@ -0,0 +1,239 @@
+package secretbox
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+// The streaming format produced by NewWriter and consumed by NewReader is:
+//
+//	prefix || chunk_0 || chunk_1 || ... || chunk_n
+//
+// prefix is 16 random bytes, chosen once per stream. The plaintext is split
+// into chunks of ChunkSize bytes; only the final chunk may be shorter, and it
+// is empty only if the entire plaintext is empty. Each chunk is encrypted with
+// Seal, so it appears on the wire as Overhead+len(plaintext) bytes.
+//
+// The nonce for chunk i is
+//
+//	prefix || uint56_be(i) || flag
+//
+// where flag is 1 for the final chunk and 0 for every other chunk. A reader
+// therefore detects reordered or dropped chunks (the counter won't match)
+// and truncation at a chunk boundary (the last chunk it sees won't have the
+// final flag set).
+
+const (
+	// ChunkSize is the maximum number of plaintext bytes in a single chunk of
+	// a stream written by NewWriter.
+	ChunkSize = 16 * 1024
+
+	// streamPrefixSize is the number of random bytes written at the start of
+	// a stream.
+	streamPrefixSize = 16
+
+	sealedChunkSize = ChunkSize + Overhead
+	maxChunkCounter = 1<<56 - 1
+)
+
+var (
+	errStreamClosed       = errors.New("secretbox: write to closed stream")
+	errStreamTooLong      = errors.New("secretbox: stream has too many chunks")
+	errStreamInvalidChunk = errors.New("secretbox: could not decrypt invalid stream chunk")
+)
+
+// chunkNonce returns the nonce for chunk number counter of the stream that
+// starts with prefix.
+func chunkNonce(prefix *[streamPrefixSize]byte, counter uint64, last bool) nacl.Nonce {
+	nonce := new([nacl.NonceSize]byte)
+	copy(nonce[:], prefix[:])
+	var c [8]byte
+	binary.BigEndian.PutUint64(c[:], counter)
+	// counter is at most maxChunkCounter, so the top byte is always zero.
+	copy(nonce[streamPrefixSize:], c[1:])
+	if last {
+		nonce[nacl.NonceSize-1] = 1
+	}
+	return nonce
+}
+
+type writer struct {
+	w       io.Writer
+	key     nacl.Key
+	prefix  [streamPrefixSize]byte
+	counter uint64
+	started bool
+	closed  bool
+	err     error
+
+	buf [ChunkSize]byte
+	n   int
+	out [sealedChunkSize]byte
+}
+
+// NewWriter returns an io.WriteCloser that encrypts data written to it with
+// key and writes the result to w, in chunks of at most ChunkSize bytes. The
+// stream must be closed to write the final chunk; Close does not close w.
+//
+// A random prefix is generated for every stream, so the same key may be used
+// to encrypt many streams. NewWriter panics if it cannot read enough random
+// data.
+func NewWriter(w io.Writer, key nacl.Key) io.WriteCloser {
+	sw := &writer{w: w, key: key}
+	randombytes.MustRead(sw.prefix[:])
+	return sw
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errStreamClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	total := 0
+	for len(p) > 0 {
+		// Only flush a full chunk once we know more data follows it, so
+		// that the final chunk can be marked as such in Close.
+		if w.n == ChunkSize {
+			if err := w.flush(false); err != nil {
+				return total, err
+			}
+		}
+		n := copy(w.buf[w.n:], p)
+		w.n += n
+		p = p[n:]
+		total += n
+	}
+	return total, nil
+}
+
+// Close encrypts and writes any buffered data as the final chunk of the
+// stream.
+func (w *writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	if w.err == nil {
+		w.flush(true)
+	}
+	w.closed = true
+	return w.err
+}
+
+func (w *writer) flush(last bool) error {
+	if w.counter > maxChunkCounter {
+		w.err = errStreamTooLong
+		return w.err
+	}
+	if !w.started {
+		if _, err := w.w.Write(w.prefix[:]); err != nil {
+			w.err = err
+			return err
+		}
+		w.started = true
+	}
+	nonce := chunkNonce(&w.prefix, w.counter, last)
+	sealed := Seal(w.out[:0], w.buf[:w.n], nonce, w.key)
+	if _, err := w.w.Write(sealed); err != nil {
+		w.err = err
+		return err
+	}
+	w.counter++
+	w.n = 0
+	return nil
+}
+
+type reader struct {
+	r       io.Reader
+	key     nacl.Key
+	prefix  [streamPrefixSize]byte
+	counter uint64
+	started bool
+	err     error
+
+	// buf holds one sealed chunk plus a byte of lookahead, which tells us
+	// whether the chunk is the final one.
+	buf   [sealedChunkSize + 1]byte
+	n     int
+	plain [ChunkSize]byte
+	out   []byte
+}
+
+// NewReader returns an io.Reader that decrypts and authenticates a stream
+// produced by NewWriter, reading it from r. Read returns io.EOF only after
+// the final chunk has been authenticated; a stream that has been truncated,
+// reordered or tampered with results in an error instead.
+//
+// Data returned by Read has been authenticated, but an attacker may still
+// truncate the stream at a chunk boundary before the final chunk, so callers
+// should not act on the plaintext until Read returns io.EOF.
+func NewReader(r io.Reader, key nacl.Key) io.Reader {
+	return &reader{r: r, key: key}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.readChunk()
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// readChunk reads, authenticates and decrypts the next chunk into r.out. It
+// returns io.EOF after decrypting the final chunk.
+func (r *reader) readChunk() error {
+	if !r.started {
+		if _, err := io.ReadFull(r.r, r.prefix[:]); err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		r.started = true
+	}
+	if r.counter > maxChunkCounter {
+		return errStreamTooLong
+	}
+
+	n, err := io.ReadFull(r.r, r.buf[r.n:])
+	r.n += n
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	default:
+		return err
+	}
+
+	chunk := r.buf[:sealedChunkSize]
+	if last {
+		chunk = r.buf[:r.n]
+		if len(chunk) == 0 {
+			// The stream ended without a final chunk.
+			return io.ErrUnexpectedEOF
+		}
+	}
+	nonce := chunkNonce(&r.prefix, r.counter, last)
+	out, ok := Open(r.plain[:0], chunk, nonce, r.key)
+	if !ok {
+		return errStreamInvalidChunk
+	}
+	r.out = out
+	if last {
+		return io.EOF
+	}
+	r.buf[0] = r.buf[sealedChunkSize]
+	r.n = 1
+	r.counter++
+	return nil
+}
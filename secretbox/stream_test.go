@@ -0,0 +1,131 @@
+package secretbox
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+func sealStream(t testing.TB, message []byte, key nacl.Key) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf, key)
+	// Write in uneven pieces to exercise the chunk buffering.
+	for p := message; len(p) > 0; {
+		n := min(len(p), 1000)
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestStreamRoundtrip(t *testing.T) {
+	key := nacl.NewKey()
+	sizes := []int{0, 1, 100, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3 * ChunkSize, 3*ChunkSize + 17}
+	for _, size := range sizes {
+		message := make([]byte, size)
+		randombytes.MustRead(message)
+		sealed := sealStream(t, message, key)
+
+		chunks := max((size+ChunkSize-1)/ChunkSize, 1)
+		if want := streamPrefixSize + size + chunks*Overhead; len(sealed) != want {
+			t.Errorf("%d: got sealed length %d, want %d", size, len(sealed), want)
+		}
+
+		opened, err := io.ReadAll(NewReader(bytes.NewReader(sealed), key))
+		if err != nil {
+			t.Errorf("%d: failed to open stream: %v", size, err)
+			continue
+		}
+		if !bytes.Equal(opened, message) {
+			t.Errorf("%d: opened stream does not match message", size)
+		}
+	}
+}
+
+func TestStreamUniquePrefix(t *testing.T) {
+	key := nacl.NewKey()
+	a := sealStream(t, []byte("hello world"), key)
+	b := sealStream(t, []byte("hello world"), key)
+	if bytes.Equal(a[:streamPrefixSize], b[:streamPrefixSize]) {
+		t.Errorf("two streams used the same prefix")
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	key := nacl.NewKey()
+	message := make([]byte, 2*ChunkSize+100)
+	randombytes.MustRead(message)
+	sealed := sealStream(t, message, key)
+
+	open := func(b []byte) error {
+		_, err := io.ReadAll(NewReader(bytes.NewReader(b), key))
+		return err
+	}
+
+	for _, i := range []int{0, streamPrefixSize, streamPrefixSize + sealedChunkSize + 5, len(sealed) - 1} {
+		sealed[i] ^= 0x20
+		if err := open(sealed); err == nil {
+			t.Errorf("opened stream with byte %d corrupted", i)
+		}
+		sealed[i] ^= 0x20
+	}
+
+	// Truncation at every chunk boundary, and in the middle of a chunk.
+	for _, n := range []int{0, streamPrefixSize - 1, streamPrefixSize, streamPrefixSize + sealedChunkSize, streamPrefixSize + 2*sealedChunkSize, len(sealed) - 1} {
+		if err := open(sealed[:n]); err == nil {
+			t.Errorf("opened stream truncated to %d bytes", n)
+		}
+	}
+
+	// Swap the first two chunks.
+	reordered := make([]byte, 0, len(sealed))
+	first := sealed[streamPrefixSize : streamPrefixSize+sealedChunkSize]
+	second := sealed[streamPrefixSize+sealedChunkSize : streamPrefixSize+2*sealedChunkSize]
+	reordered = append(reordered, sealed[:streamPrefixSize]...)
+	reordered = append(reordered, second...)
+	reordered = append(reordered, first...)
+	reordered = append(reordered, sealed[streamPrefixSize+2*sealedChunkSize:]...)
+	if err := open(reordered); err == nil {
+		t.Errorf("opened stream with reordered chunks")
+	}
+
+	// Appending data after the final chunk is detected.
+	if err := open(append(sealed[:len(sealed):len(sealed)], 0)); err == nil {
+		t.Errorf("opened stream with trailing data")
+	}
+
+	if err := open(sealed); err != nil {
+		t.Errorf("could not open original stream: %v", err)
+	}
+}
+
+func TestStreamWriteAfterClose(t *testing.T) {
+	w := NewWriter(io.Discard, nacl.NewKey())
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Errorf("expected error writing to closed stream, got nil")
+	}
+}
+
+func BenchmarkStreamWriter(b *testing.B) {
+	key := nacl.NewKey()
+	message := make([]byte, 1<<20)
+	b.SetBytes(int64(len(message)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := NewWriter(io.Discard, key)
+		w.Write(message)
+		w.Close()
+	}
+}
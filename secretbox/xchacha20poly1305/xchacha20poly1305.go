@@ -0,0 +1,121 @@
+/*
+Package xchacha20poly1305 encrypts and authenticates small messages with
+XChaCha20 and Poly1305.
+
+It is a drop-in alternative to the secretbox package, with the same API, that
+uses HChaCha20 and XChaCha20 in place of HSalsa20 and XSalsa20. The length of
+messages is not hidden.
+
+It is the caller's responsibility to ensure the uniqueness of nonces—for
+example, by using nonce 1 for the first message, nonce 2 for the second
+message, etc. Nonces are long enough that randomly generated nonces have
+negligible risk of collision.
+
+This package is interoperable with libsodium's
+crypto_secretbox_xchacha20poly1305:
+https://doc.libsodium.org/secret-key_cryptography/secretbox#alternative-api.
+It is not the same construction as the IETF XChaCha20-Poly1305 AEAD in
+golang.org/x/crypto/chacha20poly1305.
+*/
+package xchacha20poly1305 // import "github.com/kevinburke/nacl/secretbox/xchacha20poly1305"
+
+import (
+	"errors"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/internal/subtle"
+	"github.com/kevinburke/nacl/onetimeauth"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Overhead is the number of bytes of overhead when boxing a message.
+const Overhead = onetimeauth.Size
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes. If the
+// original slice has sufficient capacity then no allocation is performed.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// setup returns an XChaCha20 cipher for nonce and key, along with the
+// Poly1305 key taken from the first 32 bytes of its keystream. The cipher is
+// positioned to encrypt the message starting at the 33rd byte.
+func setup(nonce nacl.Nonce, key nacl.Key) (*chacha20.Cipher, *[32]byte) {
+	c, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		panic(err)
+	}
+	poly1305Key := new([32]byte)
+	c.XORKeyStream(poly1305Key[:], poly1305Key[:])
+	return c, poly1305Key
+}
+
+// EasySeal encrypts message using key. A 24-byte nonce is generated and
+// prepended to the output. The key and nonce pair must be unique for each
+// distinct message, and the output will be Overhead+24 bytes longer than
+// message.
+func EasySeal(message []byte, key nacl.Key) []byte {
+	nonce := nacl.NewNonce()
+	return Seal(nonce[:], message, nonce, key)
+}
+
+// Seal appends an encrypted and authenticated copy of message to out, which
+// must not overlap message. The key and nonce pair must be unique for each
+// distinct message and the output will be Overhead bytes longer than message.
+func Seal(out, message []byte, nonce nacl.Nonce, key nacl.Key) []byte {
+	ret, out := sliceForAppend(out, len(message)+Overhead)
+	if subtle.AnyOverlap(out, message) {
+		panic("nacl: invalid buffer overlap")
+	}
+	c, poly1305Key := setup(nonce, key)
+	ciphertext := out[Overhead:]
+	c.XORKeyStream(ciphertext, message)
+	tag := onetimeauth.Sum(ciphertext, poly1305Key)
+	copy(out, tag[:])
+	return ret
+}
+
+var errInvalidInput = errors.New("xchacha20poly1305: Could not decrypt invalid input")
+
+// EasyOpen decrypts box using key. We assume a 24-byte nonce is prepended to
+// the encrypted text in box. The key and nonce pair must be unique for each
+// distinct message.
+func EasyOpen(box []byte, key nacl.Key) ([]byte, error) {
+	if len(box) < 24 {
+		return nil, errors.New("xchacha20poly1305: message too short")
+	}
+	decryptNonce := new([24]byte)
+	copy(decryptNonce[:], box[:24])
+	decrypted, ok := Open([]byte{}, box[24:], decryptNonce, key)
+	if !ok {
+		return nil, errInvalidInput
+	}
+	return decrypted, nil
+}
+
+// Open authenticates and decrypts a box produced by Seal and appends the
+// message to out, which must not overlap box. The output will be Overhead
+// bytes smaller than box.
+func Open(out, box []byte, nonce nacl.Nonce, key nacl.Key) ([]byte, bool) {
+	if len(box) < Overhead {
+		return nil, false
+	}
+	c, poly1305Key := setup(nonce, key)
+	var tag [onetimeauth.Size]byte
+	copy(tag[:], box)
+	if !onetimeauth.Verify(&tag, box[Overhead:], poly1305Key) {
+		return nil, false
+	}
+	ret, out := sliceForAppend(out, len(box)-Overhead)
+	c.XORKeyStream(out, box[Overhead:])
+	return ret, true
+}
@@ -0,0 +1,78 @@
+package xchacha20poly1305
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+func TestEasySealOpen(t *testing.T) {
+	key := nacl.NewKey()
+
+	var box, opened []byte
+
+	for msgLen := 0; msgLen < 128; msgLen += 17 {
+		message := make([]byte, msgLen)
+		randombytes.MustRead(message)
+
+		box = EasySeal(message, key)
+		var err error
+		opened, err = EasyOpen(box, key)
+		if err != nil {
+			t.Errorf("%d: failed to open box: %v", msgLen, err)
+			continue
+		}
+
+		if !bytes.Equal(opened, message) {
+			t.Errorf("%d: got %x, expected %x", msgLen, opened, message)
+			continue
+		}
+	}
+
+	for i := range box {
+		box[i] ^= 0x20
+		_, err := EasyOpen(box, key)
+		if err == nil {
+			t.Errorf("box was opened after corrupting byte %d", i)
+		}
+		box[i] ^= 0x20
+	}
+}
+
+func TestSecretBox(t *testing.T) {
+	var key [32]byte
+	var nonce [24]byte
+	var message [100]byte
+
+	for i := range key[:] {
+		key[i] = 1
+	}
+	for i := range nonce[:] {
+		nonce[i] = 2
+	}
+	for i := range message[:] {
+		message[i] = 3
+	}
+
+	box := Seal(nil, message[:], &nonce, &key)
+	// expected was generated using libsodium's
+	// crypto_secretbox_xchacha20poly1305_easy.
+	expected, _ := hex.DecodeString("61d22084ecd25b371b9acaabac71cc19ccdc42c305dc4a7b514ba9794f123a280e13e016558afbb1db4d5115cb430648cf6535eca974ff4ea362629271537a9dced6f2ab36e528144364bf6d72d2fc9b937bc6d265074e0e586294eb8cb93b3b40cc624f2e2410f8c8922d7b4c96608267608fb8")
+	if !bytes.Equal(box, expected) {
+		t.Fatalf("box didn't match, got\n%x\n, expected\n%x", box, expected)
+	}
+
+	opened, ok := Open(nil, box, &nonce, &key)
+	if !ok {
+		t.Fatalf("failed to open box")
+	}
+	if !bytes.Equal(opened, message[:]) {
+		t.Fatalf("got %x, expected %x", opened, message)
+	}
+	if _, ok := Open(nil, box[:Overhead-1], &nonce, &key); ok {
+		t.Errorf("opened box shorter than Overhead")
+	}
+}
@@ -0,0 +1,30 @@
+package secretstream_test
+
+import (
+	"fmt"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/secretstream"
+)
+
+func Example() {
+	key := nacl.NewKey()
+
+	// The header must be sent to the receiver before the first message.
+	enc, header := secretstream.NewEncryptor(key)
+	c1 := enc.Push(nil, []byte("Arbitrary data to encrypt"), nil, secretstream.TagMessage)
+	c2 := enc.Push(nil, []byte("split into"), nil, secretstream.TagMessage)
+	c3 := enc.Push(nil, []byte("three messages"), nil, secretstream.TagFinal)
+
+	dec := secretstream.NewDecryptor(header, key)
+	for _, c := range [][]byte{c1, c2, c3} {
+		msg, tag, err := dec.Pull(nil, c, nil)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%s (final: %t)\n", msg, tag == secretstream.TagFinal)
+	}
+	// Output: Arbitrary data to encrypt (final: false)
+	// split into (final: false)
+	// three messages (final: true)
+}
@@ -0,0 +1,267 @@
+/*
+Package secretstream encrypts and authenticates a sequence of messages with
+secret-key cryptography.
+
+A stream is a series of messages encrypted with the same key. Each message
+is encrypted and authenticated with XChaCha20 and Poly1305, and the stream
+state changes after every message, so messages can't be dropped, duplicated
+or reordered without the receiver noticing. Every message carries a tag that
+can be used to mark the end of a logical set of messages (TagPush), force a
+key change (TagRekey), or mark the end of the stream (TagFinal).
+
+The sender creates an Encryptor with NewEncryptor, sends the returned header
+to the receiver, then calls Push for every message. The receiver creates a
+Decryptor from the header and the same key, then calls Pull for every
+message, in the same order.
+
+This package is interoperable with libsodium's
+crypto_secretstream_xchacha20poly1305:
+https://doc.libsodium.org/secret-key_cryptography/secretstream.
+*/
+package secretstream // import "github.com/kevinburke/nacl/secretstream"
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/onetimeauth"
+	"github.com/kevinburke/nacl/randombytes"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// KeySize is the size, in bytes, of a secretstream key.
+	KeySize = 32
+	// HeaderSize is the size, in bytes, of the header that starts a stream.
+	HeaderSize = 24
+	// Overhead is the number of bytes Push adds to each message: a one byte
+	// encrypted tag and a onetimeauth.Size byte authenticator.
+	Overhead = 1 + onetimeauth.Size
+)
+
+// Message tags. The tag is encrypted and authenticated along with the
+// message it is attached to.
+const (
+	// TagMessage is the most common tag, and adds no information about the
+	// nature of the message.
+	TagMessage byte = 0x00
+	// TagPush indicates that the message marks the end of a set of messages,
+	// but not the end of the stream.
+	TagPush byte = 0x01
+	// TagRekey derives a new key after the message is processed, "forgetting"
+	// the key used to encrypt it and all previous messages.
+	TagRekey byte = 0x02
+	// TagFinal indicates that the message marks the end of the stream, and
+	// erases the secret key used to encrypt the previous sequence.
+	TagFinal = TagPush | TagRekey
+)
+
+const (
+	counterSize = 4
+	inonceSize  = 8
+)
+
+var (
+	errTooShort     = errors.New("secretstream: message too short")
+	errInvalidInput = errors.New("secretstream: Could not decrypt invalid input")
+)
+
+var pad0 [16]byte
+
+// state is the state shared between an Encryptor and a Decryptor.
+type state struct {
+	key [KeySize]byte
+	// nonce is the 4-byte little-endian message counter followed by the
+	// 8-byte "inonce".
+	nonce [counterSize + inonceSize]byte
+}
+
+func (s *state) init(header *[HeaderSize]byte, key nacl.Key) {
+	subKey, err := chacha20.HChaCha20(key[:], header[:16])
+	if err != nil {
+		panic(err)
+	}
+	copy(s.key[:], subKey)
+	s.resetCounter()
+	copy(s.nonce[counterSize:], header[16:])
+}
+
+func (s *state) resetCounter() {
+	clear(s.nonce[:counterSize])
+	s.nonce[0] = 1
+}
+
+// rekey derives a new key and inonce from the current ones.
+func (s *state) rekey() {
+	var next [KeySize + inonceSize]byte
+	copy(next[:], s.key[:])
+	copy(next[KeySize:], s.nonce[counterSize:])
+	s.xor(next[:], next[:], 0)
+	copy(s.key[:], next[:KeySize])
+	copy(s.nonce[counterSize:], next[KeySize:])
+	s.resetCounter()
+}
+
+// xor XORs in with the ChaCha20 keystream, starting at block counter, and
+// writes the result to out.
+func (s *state) xor(out, in []byte, counter uint32) {
+	c, err := chacha20.NewUnauthenticatedCipher(s.key[:], s.nonce[:])
+	if err != nil {
+		panic(err)
+	}
+	c.SetCounter(counter)
+	c.XORKeyStream(out, in)
+}
+
+// mac computes the authenticator for an encrypted tag block and ciphertext.
+func (s *state) mac(additionalData []byte, block *[64]byte, ciphertext []byte) *[onetimeauth.Size]byte {
+	var polyKey [32]byte
+	s.xor(polyKey[:], polyKey[:], 0)
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(block)+len(ciphertext)))
+	mac := onetimeauth.New(&polyKey)
+	mac.Write(additionalData)
+	mac.Write(pad0[:(0x10-len(additionalData))&0xf])
+	mac.Write(block[:])
+	mac.Write(ciphertext)
+	// libsodium adds, rather than subtracts, the ciphertext length here, so
+	// the padding doesn't always align to 16 bytes. Match it.
+	mac.Write(pad0[:(0x10-len(block)+len(ciphertext))&0xf])
+	mac.Write(lengths[:])
+	tag := new([onetimeauth.Size]byte)
+	mac.Sum(tag[:0])
+	return tag
+}
+
+// advance updates the state after a message with the given tag and
+// authenticator has been processed.
+func (s *state) advance(tag byte, mac *[onetimeauth.Size]byte) {
+	for i := range inonceSize {
+		s.nonce[counterSize+i] ^= mac[i]
+	}
+	counter := binary.LittleEndian.Uint32(s.nonce[:counterSize]) + 1
+	binary.LittleEndian.PutUint32(s.nonce[:counterSize], counter)
+	if tag&TagRekey != 0 || counter == 0 {
+		s.rekey()
+	}
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes. If the
+// original slice has sufficient capacity then no allocation is performed.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// Encryptor encrypts the messages of a single stream.
+type Encryptor struct {
+	s state
+}
+
+// NewEncryptor returns an Encryptor for a new stream encrypted with key, and
+// the header that must be sent to the receiver before the first message. The
+// header is generated randomly, so the same key may be used for many streams.
+// NewEncryptor panics if it cannot read enough random data.
+func NewEncryptor(key nacl.Key) (*Encryptor, *[HeaderSize]byte) {
+	header := new([HeaderSize]byte)
+	randombytes.MustRead(header[:])
+	return newEncryptor(header, key), header
+}
+
+func newEncryptor(header *[HeaderSize]byte, key nacl.Key) *Encryptor {
+	e := new(Encryptor)
+	e.s.init(header, key)
+	return e
+}
+
+// Push encrypts and authenticates message and tag, along with the optional
+// additionalData, and appends the result to out, which must not overlap
+// message. The output will be Overhead bytes longer than message.
+// additionalData is authenticated but not encrypted, and must be passed to
+// Pull unchanged.
+func (e *Encryptor) Push(out, message, additionalData []byte, tag byte) []byte {
+	ret, out := sliceForAppend(out, len(message)+Overhead)
+
+	var block [64]byte
+	block[0] = tag
+	e.s.xor(block[:], block[:], 1)
+	out[0] = block[0]
+
+	ciphertext := out[1 : 1+len(message)]
+	e.s.xor(ciphertext, message, 2)
+	mac := e.s.mac(additionalData, &block, ciphertext)
+	copy(out[1+len(message):], mac[:])
+
+	e.s.advance(tag, mac)
+	return ret
+}
+
+// Rekey explicitly derives a new key for the stream. The receiver must call
+// Rekey on its Decryptor at the same point in the stream.
+func (e *Encryptor) Rekey() {
+	e.s.rekey()
+}
+
+// Decryptor decrypts the messages of a single stream.
+type Decryptor struct {
+	s state
+}
+
+// NewDecryptor returns a Decryptor for the stream that starts with header
+// and was encrypted with key.
+func NewDecryptor(header *[HeaderSize]byte, key nacl.Key) *Decryptor {
+	d := new(Decryptor)
+	d.s.init(header, key)
+	return d
+}
+
+// Pull authenticates and decrypts the next message in the stream, which must
+// have been produced by Push with the same additionalData. It appends the
+// message to out, which must not overlap ciphertext, and returns it along
+// with the tag attached to it. The output will be Overhead bytes smaller than
+// ciphertext.
+//
+// If the message can't be authenticated, the state of the Decryptor is not
+// changed and the next message can be tried instead.
+func (d *Decryptor) Pull(out, ciphertext, additionalData []byte) ([]byte, byte, error) {
+	if len(ciphertext) < Overhead {
+		return nil, 0, errTooShort
+	}
+	mlen := len(ciphertext) - Overhead
+
+	var block [64]byte
+	block[0] = ciphertext[0]
+	d.s.xor(block[:], block[:], 1)
+	tag := block[0]
+	block[0] = ciphertext[0]
+
+	c := ciphertext[1 : 1+mlen]
+	mac := d.s.mac(additionalData, &block, c)
+	if subtle.ConstantTimeCompare(mac[:], ciphertext[1+mlen:]) != 1 {
+		return nil, 0, errInvalidInput
+	}
+
+	ret, out := sliceForAppend(out, mlen)
+	d.s.xor(out, c, 2)
+
+	d.s.advance(tag, mac)
+	return ret, tag, nil
+}
+
+// Rekey explicitly derives a new key for the stream, matching a call to Rekey
+// by the sender.
+func (d *Decryptor) Rekey() {
+	d.s.rekey()
+}
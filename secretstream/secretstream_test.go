@@ -0,0 +1,163 @@
+package secretstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/kevinburke/nacl"
+)
+
+func mustDecode(t testing.TB, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// libsodiumStream was generated with libsodium 1.0.18, using a key of the
+// bytes 0x00..0x1f. The stream is explicitly rekeyed after the fourth message.
+var libsodiumHeader = "8c17964733ee6054b644d9786c1ac1c7c8f3d38580b563c9"
+
+var libsodiumStream = []struct {
+	msg   string
+	ad    string
+	tag   byte
+	out   string
+	rekey bool
+}{
+	{"", "", TagMessage, "ccf48da1b3bc53483b53f326b5e84289d1", false},
+	{"Arbitrary data to encrypt", "", TagMessage, "5b21f9d2cef22fc98ec0ea4f0bbadb39d4eb269f43a3e57f70199fc5a65b88bf3b5d434cdd1ee6069044", false},
+	{"split into", "ad", TagPush, "8f6d30bdfaac16d5d8b27e809a7b8773b9eda8d9f062ada52e4a99", false},
+	{"three messages", "", TagRekey, "8e51ea3d7c98a8ade3e9eb37286130507014641de85e186f958ae13bef3c65", true},
+	{strings.Repeat("x", 100), "more ad", TagMessage, "e263884c85d70507fb08d31a28111c9265cd64a707776b6097d74d770c9334415ef48647abb23de7b74586a4ee59e7a9fed29704078abb49ee09f78a6c19dd584ee41bd3f3b61b54e21496badeb67e7846156ba717ab41a95b8812101d365115318447994478bc8633815b9e89bc400a0d724af714", false},
+	{"final", "", TagFinal, "4b81670b5fbdfb868b7bc62d170b87d53158266e6a87", false},
+}
+
+func testKey() nacl.Key {
+	key := new([KeySize]byte)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func TestLibsodiumPush(t *testing.T) {
+	var header [HeaderSize]byte
+	copy(header[:], mustDecode(t, libsodiumHeader))
+	e := newEncryptor(&header, testKey())
+	for i, tt := range libsodiumStream {
+		out := e.Push(nil, []byte(tt.msg), []byte(tt.ad), tt.tag)
+		if got := hex.EncodeToString(out); got != tt.out {
+			t.Errorf("%d: Push: got %s, want %s", i, got, tt.out)
+		}
+		if tt.rekey {
+			e.Rekey()
+		}
+	}
+}
+
+func TestLibsodiumPull(t *testing.T) {
+	var header [HeaderSize]byte
+	copy(header[:], mustDecode(t, libsodiumHeader))
+	d := NewDecryptor(&header, testKey())
+	for i, tt := range libsodiumStream {
+		msg, tag, err := d.Pull(nil, mustDecode(t, tt.out), []byte(tt.ad))
+		if err != nil {
+			t.Fatalf("%d: Pull: %v", i, err)
+		}
+		if string(msg) != tt.msg {
+			t.Errorf("%d: Pull: got message %q, want %q", i, msg, tt.msg)
+		}
+		if tag != tt.tag {
+			t.Errorf("%d: Pull: got tag %d, want %d", i, tag, tt.tag)
+		}
+		if tt.rekey {
+			d.Rekey()
+		}
+	}
+}
+
+func TestPushPull(t *testing.T) {
+	key := nacl.NewKey()
+	e, header := NewEncryptor(key)
+	d := NewDecryptor(header, key)
+
+	var boxes [][]byte
+	for i := range 10 {
+		tag := TagMessage
+		if i == 9 {
+			tag = TagFinal
+		}
+		boxes = append(boxes, e.Push(nil, bytes.Repeat([]byte{byte(i)}, i*31), []byte("ad"), tag))
+	}
+
+	// Messages out of order are rejected, and don't change the state.
+	if _, _, err := d.Pull(nil, boxes[1], []byte("ad")); err == nil {
+		t.Errorf("Pull accepted a message out of order")
+	}
+	for i, box := range boxes {
+		for j := range box {
+			box[j] ^= 0x40
+			if _, _, err := d.Pull(nil, box, []byte("ad")); err == nil {
+				t.Fatalf("%d: Pull accepted message with byte %d corrupted", i, j)
+			}
+			box[j] ^= 0x40
+		}
+		if _, _, err := d.Pull(nil, box, []byte("wrong ad")); err == nil {
+			t.Errorf("%d: Pull accepted message with wrong additional data", i)
+		}
+		msg, tag, err := d.Pull(nil, box, []byte("ad"))
+		if err != nil {
+			t.Fatalf("%d: Pull: %v", i, err)
+		}
+		if !bytes.Equal(msg, bytes.Repeat([]byte{byte(i)}, i*31)) {
+			t.Errorf("%d: Pull: wrong message %x", i, msg)
+		}
+		if (i == 9) != (tag == TagFinal) {
+			t.Errorf("%d: Pull: got tag %d", i, tag)
+		}
+	}
+
+	if _, _, err := d.Pull(nil, make([]byte, Overhead-1), nil); err == nil {
+		t.Errorf("Pull accepted a short message")
+	}
+}
+
+func TestCounterWrap(t *testing.T) {
+	key := nacl.NewKey()
+	e, header := NewEncryptor(key)
+	d := NewDecryptor(header, key)
+	// Pretend 2^32-1 messages have been sent; the next message must trigger
+	// an automatic rekey on both sides.
+	for _, s := range []*state{&e.s, &d.s} {
+		copy(s.nonce[:counterSize], []byte{0xff, 0xff, 0xff, 0xff})
+	}
+	for i := range 3 {
+		box := e.Push(nil, []byte("message"), nil, TagMessage)
+		if _, _, err := d.Pull(nil, box, nil); err != nil {
+			t.Fatalf("%d: Pull: %v", i, err)
+		}
+	}
+	if e.s != d.s {
+		t.Errorf("encryptor and decryptor states diverged")
+	}
+	if e.s.nonce[0] != 3 {
+		t.Errorf("expected counter to be reset by rekey, got %x", e.s.nonce[:counterSize])
+	}
+}
+
+func BenchmarkPush1K(b *testing.B) {
+	e, _ := NewEncryptor(nacl.NewKey())
+	msg := make([]byte, 1024)
+	out := make([]byte, 0, len(msg)+Overhead)
+	b.SetBytes(int64(len(msg)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = e.Push(out[:0], msg, nil, TagMessage)
+	}
+}
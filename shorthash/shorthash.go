@@ -0,0 +1,186 @@
+/*
+Package shorthash computes short, keyed hashes of short messages, using
+SipHash-2-4.
+
+shorthash is designed for hash tables and other data structures that need a
+fast hash whose output an attacker can't predict without the key, so they
+can't force collisions. The output is too short for shorthash to be used as a
+collision-resistant hash, or as a general-purpose MAC.
+
+Sum returns a 64-bit hash, and Sum128 a 128-bit hash computed with the
+SipHash-2-4 variant libsodium calls siphashx24. New returns a hash.Hash64
+that computes the same 64-bit hash as Sum incrementally.
+
+This package is interoperable with libsodium's crypto_shorthash and
+crypto_shorthash_siphashx24: https://doc.libsodium.org/hashing/short-input_hashing.
+*/
+package shorthash // import "github.com/kevinburke/nacl/shorthash"
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// KeySize is the size, in bytes, of a key.
+	KeySize = 16
+	// Size is the size, in bytes, of the result of Sum.
+	Size = 8
+	// Size128 is the size, in bytes, of the result of Sum128.
+	Size128 = 16
+
+	blockSize = 8
+)
+
+type state struct {
+	v0, v1, v2, v3 uint64
+}
+
+func newState(key *[KeySize]byte, wide bool) state {
+	k0 := binary.LittleEndian.Uint64(key[0:])
+	k1 := binary.LittleEndian.Uint64(key[8:])
+	s := state{
+		v0: k0 ^ 0x736f6d6570736575,
+		v1: k1 ^ 0x646f72616e646f6d,
+		v2: k0 ^ 0x6c7967656e657261,
+		v3: k1 ^ 0x7465646279746573,
+	}
+	if wide {
+		s.v1 ^= 0xee
+	}
+	return s
+}
+
+func (s *state) round() {
+	s.v0 += s.v1
+	s.v1 = bits.RotateLeft64(s.v1, 13)
+	s.v1 ^= s.v0
+	s.v0 = bits.RotateLeft64(s.v0, 32)
+	s.v2 += s.v3
+	s.v3 = bits.RotateLeft64(s.v3, 16)
+	s.v3 ^= s.v2
+	s.v0 += s.v3
+	s.v3 = bits.RotateLeft64(s.v3, 21)
+	s.v3 ^= s.v0
+	s.v2 += s.v1
+	s.v1 = bits.RotateLeft64(s.v1, 17)
+	s.v1 ^= s.v2
+	s.v2 = bits.RotateLeft64(s.v2, 32)
+}
+
+// compress absorbs one 8-byte block, with two rounds.
+func (s *state) compress(m uint64) {
+	s.v3 ^= m
+	s.round()
+	s.round()
+	s.v0 ^= m
+}
+
+// blocks absorbs all of the complete blocks in p.
+func (s *state) blocks(p []byte) {
+	for len(p) >= blockSize {
+		s.compress(binary.LittleEndian.Uint64(p))
+		p = p[blockSize:]
+	}
+}
+
+// last absorbs the final block: the remaining bytes of the message, with the
+// low byte of the message length in the top byte.
+func (s *state) last(tail []byte, length uint64) {
+	var b [blockSize]byte
+	copy(b[:], tail)
+	b[7] = byte(length)
+	s.compress(binary.LittleEndian.Uint64(b[:]))
+}
+
+// finalize runs the four finalization rounds; xor is 0xff for SipHash, and
+// 0xee for the first half of the 128-bit variant.
+func (s *state) finalize(xor uint64) uint64 {
+	s.v2 ^= xor
+	s.round()
+	s.round()
+	s.round()
+	s.round()
+	return s.v0 ^ s.v1 ^ s.v2 ^ s.v3
+}
+
+// Sum returns the SipHash-2-4 hash of m under key.
+func Sum(m []byte, key *[KeySize]byte) *[Size]byte {
+	s := newState(key, false)
+	n := len(m) &^ (blockSize - 1)
+	s.blocks(m[:n])
+	s.last(m[n:], uint64(len(m)))
+	out := new([Size]byte)
+	binary.LittleEndian.PutUint64(out[:], s.finalize(0xff))
+	return out
+}
+
+// Sum128 returns the 128-bit SipHash-2-4 hash of m under key.
+func Sum128(m []byte, key *[KeySize]byte) *[Size128]byte {
+	s := newState(key, true)
+	n := len(m) &^ (blockSize - 1)
+	s.blocks(m[:n])
+	s.last(m[n:], uint64(len(m)))
+	out := new([Size128]byte)
+	binary.LittleEndian.PutUint64(out[0:], s.finalize(0xee))
+	s.v1 ^= 0xdd
+	binary.LittleEndian.PutUint64(out[8:], s.finalize(0))
+	return out
+}
+
+type digest struct {
+	key    [KeySize]byte
+	s      state
+	block  [blockSize]byte
+	offset int
+	length uint64
+}
+
+// New returns a hash.Hash64 computing the same hash as Sum. Its Sum method
+// appends the hash in the same byte order as Sum, little-endian; Sum64
+// returns it as an integer.
+func New(key *[KeySize]byte) hash.Hash64 {
+	d := &digest{key: *key}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) BlockSize() int { return blockSize }
+
+func (d *digest) Reset() {
+	d.s = newState(&d.key, false)
+	d.offset = 0
+	d.length = 0
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.length += uint64(n)
+	if d.offset > 0 {
+		c := copy(d.block[d.offset:], p)
+		d.offset += c
+		p = p[c:]
+		if d.offset < blockSize {
+			return n, nil
+		}
+		d.s.blocks(d.block[:])
+		d.offset = 0
+	}
+	full := len(p) &^ (blockSize - 1)
+	d.s.blocks(p[:full])
+	d.offset = copy(d.block[:], p[full:])
+	return n, nil
+}
+
+func (d *digest) Sum64() uint64 {
+	s := d.s
+	s.last(d.block[:d.offset], d.length)
+	return s.finalize(0xff)
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	return binary.LittleEndian.AppendUint64(b, d.Sum64())
+}
@@ -0,0 +1,138 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/sha512"
+
+	"filippo.io/edwards25519"
+	"github.com/kevinburke/nacl/randombytes"
+)
+
+// VerifyBatch reports whether signatures[i] is a valid signature of
+// messages[i] by publicKeys[i], for every i. It is considerably faster than
+// calling VerifyDetached on each entry in turn.
+//
+// The entries are checked together with a single randomized multi-scalar
+// multiplication. If that check fails, VerifyBatch falls back to verifying
+// each entry on its own, and valid reports which entries passed; ok is true
+// only if every entry is valid. A nil signature, or an input that can't be
+// decoded, is reported as invalid.
+//
+// Both the batch check and the per-entry check use the cofactored
+// verification equation, [8][s]B = [8]R + [8][k]A, so whether an entry is
+// valid does not depend on the other entries in the batch. This means
+// VerifyBatch accepts signatures with a small-order component that
+// VerifyDetached, which uses the cofactorless equation, rejects. Such
+// signatures can only be created deliberately, by the holder of the private
+// key.
+//
+// VerifyBatch panics if the three slices have different lengths. It panics
+// if it cannot read enough random data.
+func VerifyBatch(publicKeys []PublicKey, messages [][]byte, signatures []*[SignatureSize]byte) (ok bool, valid []bool) {
+	if len(publicKeys) != len(messages) || len(messages) != len(signatures) {
+		panic("sign: VerifyBatch called with slices of different lengths")
+	}
+	valid = make([]bool, len(signatures))
+	entries := make([]batchEntry, len(signatures))
+	decoded := true
+	for i := range entries {
+		decoded = entries[i].decode(publicKeys[i], messages[i], signatures[i]) && decoded
+	}
+	if decoded && verifyBatch(entries) {
+		for i := range valid {
+			valid[i] = true
+		}
+		return true, valid
+	}
+
+	ok = true
+	for i := range entries {
+		valid[i] = entries[i].ok && entries[i].verify()
+		ok = ok && valid[i]
+	}
+	return ok, valid
+}
+
+// batchEntry is a decoded signature, with k = SHA-512(R || A || M).
+type batchEntry struct {
+	ok   bool
+	A, R *edwards25519.Point
+	s, k *edwards25519.Scalar
+}
+
+// decode decodes a signature into e, and reports whether it could be
+// decoded. It applies the same encoding checks as VerifyDetached.
+func (e *batchEntry) decode(publicKey PublicKey, message []byte, sig *[SignatureSize]byte) bool {
+	if sig == nil || len(publicKey) != PublicKeySize || sig[63]&224 != 0 {
+		return false
+	}
+	A, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil {
+		return false
+	}
+	R, err := new(edwards25519.Point).SetBytes(sig[:32])
+	// Single verification compares the encoding of R, so reject
+	// non-canonical encodings here as well.
+	if err != nil || !bytes.Equal(R.Bytes(), sig[:32]) {
+		return false
+	}
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+
+	h := sha512.New()
+	h.Write(sig[:32])
+	h.Write(publicKey)
+	h.Write(message)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	*e = batchEntry{ok: true, A: A, R: R, s: s, k: k}
+	return true
+}
+
+// verify checks the cofactored equation [8]([s]B - R - [k]A) == 0 for a
+// single decoded entry.
+func (e *batchEntry) verify() bool {
+	minusK := edwards25519.NewScalar().Negate(e.k)
+	check := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(minusK, e.A, e.s)
+	check.Subtract(check, e.R)
+	check.MultByCofactor(check)
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// verifyBatch checks that
+//
+//	[8](-sum(z_i * s_i) * B + sum(z_i * R_i) + sum(z_i * k_i * A_i)) == 0
+//
+// for random 128-bit scalars z_i. Every entry must have been decoded.
+func verifyBatch(entries []batchEntry) bool {
+	n := len(entries)
+	// The scalars and points are laid out as B, R_0..R_n-1, A_0..A_n-1.
+	scalars := make([]*edwards25519.Scalar, 1+2*n)
+	points := make([]*edwards25519.Point, 1+2*n)
+	points[0] = edwards25519.NewGeneratorPoint()
+	sum := edwards25519.NewScalar()
+
+	var zBytes [32]byte
+	for i, e := range entries {
+		randombytes.MustRead(zBytes[:16])
+		z, err := edwards25519.NewScalar().SetCanonicalBytes(zBytes[:])
+		if err != nil {
+			panic(err)
+		}
+
+		sum.MultiplyAdd(z, e.s, sum)
+		scalars[1+i] = z
+		points[1+i] = e.R
+		scalars[1+n+i] = edwards25519.NewScalar().Multiply(z, e.k)
+		points[1+n+i] = e.A
+	}
+	scalars[0] = edwards25519.NewScalar().Negate(sum)
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	check.MultByCofactor(check)
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
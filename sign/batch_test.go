@@ -0,0 +1,192 @@
+package sign
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+func batchInputs(t testing.TB, n int) ([]PublicKey, [][]byte, []*[SignatureSize]byte) {
+	publicKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	signatures := make([]*[SignatureSize]byte, n)
+	for i := range n {
+		pub, priv, err := Keypair(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = pub
+		messages[i] = []byte(fmt.Sprintf("message %d", i))
+		signatures[i] = SignDetached(messages[i], priv)
+	}
+	return publicKeys, messages, signatures
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 17} {
+		publicKeys, messages, signatures := batchInputs(t, n)
+		ok, valid := VerifyBatch(publicKeys, messages, signatures)
+		if !ok {
+			t.Errorf("%d: VerifyBatch rejected valid signatures", n)
+		}
+		if len(valid) != n {
+			t.Fatalf("%d: got %d results", n, len(valid))
+		}
+		for i, v := range valid {
+			if !v {
+				t.Errorf("%d: entry %d reported invalid", n, i)
+			}
+		}
+	}
+}
+
+func TestVerifyBatchInvalid(t *testing.T) {
+	publicKeys, messages, signatures := batchInputs(t, 8)
+
+	check := func(name string, bad int) {
+		t.Helper()
+		ok, valid := VerifyBatch(publicKeys, messages, signatures)
+		if ok {
+			t.Errorf("%s: VerifyBatch accepted an invalid entry", name)
+		}
+		for i, v := range valid {
+			if v != (i != bad) {
+				t.Errorf("%s: entry %d: got valid %t", name, i, v)
+			}
+		}
+	}
+
+	messages[3] = []byte("wrong message")
+	check("wrong message", 3)
+	messages[3] = []byte("message 3")
+
+	signatures[5][10] ^= 0x01
+	check("corrupted R", 5)
+	signatures[5][10] ^= 0x01
+
+	signatures[6][40] ^= 0x01
+	check("corrupted s", 6)
+	signatures[6][40] ^= 0x01
+
+	signatures[0][63] |= 0xe0
+	check("non-canonical s", 0)
+	signatures[0][63] &^= 0xe0
+
+	publicKeys[7], publicKeys[6] = publicKeys[6], publicKeys[7]
+	ok, valid := VerifyBatch(publicKeys, messages, signatures)
+	if ok || valid[6] || valid[7] || !valid[5] {
+		t.Errorf("swapped keys: got ok %t, valid %v", ok, valid)
+	}
+	publicKeys[7], publicKeys[6] = publicKeys[6], publicKeys[7]
+
+	publicKeys[2] = publicKeys[2][:31]
+	check("short public key", 2)
+	publicKeys[2] = publicKeys[2][:PublicKeySize]
+
+	signatures[4] = nil
+	check("nil signature", 4)
+}
+
+// signWithTorsion signs message with the key derived from seed, but adds the
+// point of order 2 to R. The signature is valid under the cofactored
+// equation and invalid under the cofactorless one.
+func signWithTorsion(t *testing.T, seed *[SeedSize]byte, message []byte) (PublicKey, *[SignatureSize]byte) {
+	t.Helper()
+	publicKey, _ := KeyPairFromSeed(seed)
+	digest := sha512.Sum512(seed[:])
+	a, err := edwards25519.NewScalar().SetBytesWithClamping(digest[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rBytes [64]byte
+	if _, err := rand.Read(rBytes[:]); err != nil {
+		t.Fatal(err)
+	}
+	r, err := edwards25519.NewScalar().SetUniformBytes(rBytes[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	torsionBytes, _ := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	torsion, err := new(edwards25519.Point).SetBytes(torsionBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+	R.Add(R, torsion)
+
+	h := sha512.New()
+	h.Write(R.Bytes())
+	h.Write(publicKey)
+	h.Write(message)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := edwards25519.NewScalar().MultiplyAdd(k, a, r)
+
+	sig := new([SignatureSize]byte)
+	copy(sig[:32], R.Bytes())
+	copy(sig[32:], s.Bytes())
+	return publicKey, sig
+}
+
+func TestVerifyBatchSmallOrder(t *testing.T) {
+	var seed [SeedSize]byte
+	seed[0] = 1
+	message := []byte("small order R")
+	torsionKey, torsionSig := signWithTorsion(t, &seed, message)
+	if err := VerifyDetached(message, torsionSig, torsionKey); err == nil {
+		t.Fatal("VerifyDetached accepted a signature with a small-order R")
+	}
+
+	publicKeys, messages, signatures := batchInputs(t, 2)
+	publicKeys[0], messages[0], signatures[0] = torsionKey, message, torsionSig
+
+	// The verdict for the first entry must not depend on the second.
+	for i := range 20 {
+		if ok, valid := VerifyBatch(publicKeys[:1], messages[:1], signatures[:1]); !ok || !valid[0] {
+			t.Fatalf("%d: alone: got ok %t, valid %v", i, ok, valid)
+		}
+		if ok, valid := VerifyBatch(publicKeys, messages, signatures); !ok || !valid[0] || !valid[1] {
+			t.Fatalf("%d: with a valid neighbour: got ok %t, valid %v", i, ok, valid)
+		}
+		signatures[1][40] ^= 0x01
+		if ok, valid := VerifyBatch(publicKeys, messages, signatures); ok || !valid[0] || valid[1] {
+			t.Fatalf("%d: with an invalid neighbour: got ok %t, valid %v", i, ok, valid)
+		}
+		signatures[1][40] ^= 0x01
+	}
+}
+
+func benchmarkVerifyBatch(b *testing.B, n int) {
+	publicKeys, messages, signatures := batchInputs(b, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, _ := VerifyBatch(publicKeys, messages, signatures); !ok {
+			b.Fatal("VerifyBatch failed")
+		}
+	}
+}
+
+func benchmarkVerifyLoop(b *testing.B, n int) {
+	publicKeys, messages, signatures := batchInputs(b, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range signatures {
+			if err := VerifyDetached(messages[j], signatures[j], publicKeys[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyBatch8(b *testing.B)   { benchmarkVerifyBatch(b, 8) }
+func BenchmarkVerifyBatch64(b *testing.B)  { benchmarkVerifyBatch(b, 64) }
+func BenchmarkVerifyBatch256(b *testing.B) { benchmarkVerifyBatch(b, 256) }
+func BenchmarkVerifyLoop8(b *testing.B)    { benchmarkVerifyLoop(b, 8) }
+func BenchmarkVerifyLoop64(b *testing.B)   { benchmarkVerifyLoop(b, 64) }
+func BenchmarkVerifyLoop256(b *testing.B)  { benchmarkVerifyLoop(b, 256) }
@@ -0,0 +1,78 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"strconv"
+
+	"filippo.io/edwards25519"
+	"github.com/kevinburke/nacl"
+)
+
+var errInvalidPublicKey = errors.New("sign: public key is not a valid Ed25519 point")
+
+// identity and minusOne are used to check that a point is in the prime-order
+// subgroup.
+var (
+	identity = edwards25519.NewIdentityPoint()
+	minusOne = edwards25519.NewScalar().Negate(scalarOne())
+)
+
+func scalarOne() *edwards25519.Scalar {
+	var one [32]byte
+	one[0] = 1
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(one[:])
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// PublicKeyToCurve25519 converts an Ed25519 public key into the equivalent
+// Curve25519 public key, for use with the box and scalarmult packages. It is
+// compatible with libsodium's crypto_sign_ed25519_pk_to_curve25519.
+//
+// An error is returned if publicKey is not the canonical encoding of a point
+// in the prime-order subgroup; in particular, small-order points are rejected.
+func PublicKeyToCurve25519(publicKey PublicKey) (nacl.Key, error) {
+	if err := checkPublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	p, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil || !bytes.Equal(p.Bytes(), publicKey) {
+		return nil, errInvalidPublicKey
+	}
+	if new(edwards25519.Point).MultByCofactor(p).Equal(identity) == 1 {
+		return nil, errInvalidPublicKey
+	}
+	// [L]P = [L-1]P + P is the identity exactly when P has order L.
+	q := new(edwards25519.Point).ScalarMult(minusOne, p)
+	if q.Add(q, p).Equal(identity) != 1 {
+		return nil, errInvalidPublicKey
+	}
+	key := new([nacl.KeySize]byte)
+	copy(key[:], p.BytesMontgomery())
+	return key, nil
+}
+
+// PrivateKeyToCurve25519 converts an Ed25519 private key into the equivalent
+// Curve25519 private key, for use with the box and scalarmult packages. It is
+// compatible with libsodium's crypto_sign_ed25519_sk_to_curve25519. It will
+// panic if len(privateKey) is not PrivateKeySize.
+//
+// The Curve25519 public key for the result is the one PublicKeyToCurve25519
+// returns for privateKey's public key, so a single identity can be used both
+// to sign messages and to receive boxes.
+func PrivateKeyToCurve25519(privateKey PrivateKey) nacl.Key {
+	if l := len(privateKey); l != PrivateKeySize {
+		panic("sign: bad private key length: " + strconv.Itoa(l))
+	}
+	h := sha512.Sum512(privateKey[:SeedSize])
+	key := new([nacl.KeySize]byte)
+	copy(key[:], h[:nacl.KeySize])
+	key[0] &= 248
+	key[31] &= 127
+	key[31] |= 64
+	return key
+}
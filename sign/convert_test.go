@@ -0,0 +1,105 @@
+package sign
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/kevinburke/nacl/scalarmult"
+)
+
+// Generated with libsodium's crypto_sign_seed_keypair,
+// crypto_sign_ed25519_pk_to_curve25519 and crypto_sign_ed25519_sk_to_curve25519.
+var curve25519Tests = []struct {
+	seed, publicKey, curvePublicKey, curvePrivateKey string
+}{
+	{
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		"03a107bff3ce10be1d70dd18e74bc09967e4d6309ba50d5f1ddc8664125531b8",
+		"4701d08488451f545a409fb58ae3e58581ca40ac3f7f114698cd71deac73ca01",
+		"3894eea49c580aef816935762be049559d6d1440dede12e6a125f1841fff8e6f",
+	},
+	{
+		"0708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20212223242526",
+		"00d05a1d1ea251396d557afbd4588b3c6d99dbeb972fed10a32562ea26dcdcfa",
+		"c1245675bb385ab677f41db44eb7fe3b7603393acad52c63a6be238232a3f727",
+		"10174084cbded240bf5356f2f95f5dde0f8db69ccad7d59e70b871887e597a62",
+	},
+	{
+		"0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d",
+		"97f5a37b65b0b11e300e36507d7610abf36b9fffc6f217bb39e5194f9ee16e8b",
+		"86657d938731fdf562a3c8f6e910fb53b7ec05999c91f4c1430e2133f12d607c",
+		"3888b15b59fd2a6cb5f5855b17f624d22d1bd9683aceda11118e19273340eb52",
+	},
+}
+
+func TestCurve25519Conversion(t *testing.T) {
+	for i, tt := range curve25519Tests {
+		var seed [SeedSize]byte
+		b, _ := hex.DecodeString(tt.seed)
+		copy(seed[:], b)
+		publicKey, privateKey := KeyPairFromSeed(&seed)
+		if got := hex.EncodeToString(publicKey); got != tt.publicKey {
+			t.Fatalf("%d: public key: got %s, want %s", i, got, tt.publicKey)
+		}
+
+		curvePublicKey, err := PublicKeyToCurve25519(publicKey)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if got := hex.EncodeToString(curvePublicKey[:]); got != tt.curvePublicKey {
+			t.Errorf("%d: PublicKeyToCurve25519: got %s, want %s", i, got, tt.curvePublicKey)
+		}
+		curvePrivateKey := PrivateKeyToCurve25519(privateKey)
+		if got := hex.EncodeToString(curvePrivateKey[:]); got != tt.curvePrivateKey {
+			t.Errorf("%d: PrivateKeyToCurve25519: got %s, want %s", i, got, tt.curvePrivateKey)
+		}
+		if *scalarmult.Base(curvePrivateKey) != *curvePublicKey {
+			t.Errorf("%d: converted private key does not match converted public key", i)
+		}
+	}
+}
+
+func TestPublicKeyToCurve25519Invalid(t *testing.T) {
+	// All of these are rejected by libsodium as well.
+	invalid := []string{
+		// identity
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		// point of order 8
+		"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+		// y = p - 1, a point of order 2
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// non-canonical encodings of small-order points
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// not on the curve
+		"0200000000000000000000000000000000000000000000000000000000000000",
+	}
+	for _, s := range invalid {
+		b, _ := hex.DecodeString(s)
+		if _, err := PublicKeyToCurve25519(PublicKey(b)); err == nil {
+			t.Errorf("PublicKeyToCurve25519 accepted %s", s)
+		}
+	}
+
+	if _, err := PublicKeyToCurve25519(PublicKey(make([]byte, 31))); err == nil {
+		t.Errorf("PublicKeyToCurve25519 accepted a short key")
+	}
+
+	// A valid key plus a point of order 8 is on the curve and not of small
+	// order, but is outside the prime-order subgroup.
+	b, _ := hex.DecodeString(curve25519Tests[0].publicKey)
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ = hex.DecodeString(invalid[1])
+	torsion, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := new(edwards25519.Point).Add(p, torsion).Bytes()
+	if _, err := PublicKeyToCurve25519(PublicKey(mixed)); err == nil {
+		t.Errorf("PublicKeyToCurve25519 accepted a point outside the prime-order subgroup")
+	}
+}
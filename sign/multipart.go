@@ -0,0 +1,79 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"hash"
+)
+
+// phOptions selects Ed25519ph with an empty context, which is what libsodium's
+// multi-part API produces.
+var phOptions = &ed25519.Options{Hash: crypto.SHA512}
+
+// A Signer computes an Ed25519ph signature of a message that is written to it
+// in pieces, so the message never has to be held in memory all at once.
+// Ed25519ph (RFC 8032, Section 5.1) signs the SHA-512 digest of the message;
+// its signatures are not interchangeable with those produced by Sign and
+// SignDetached.
+//
+// Signer is compatible with libsodium's crypto_sign_init, crypto_sign_update
+// and crypto_sign_final_create.
+type Signer struct {
+	h hash.Hash
+}
+
+// NewSigner returns a Signer for a new message.
+func NewSigner() *Signer {
+	return &Signer{h: sha512.New()}
+}
+
+// Write adds more of the message to be signed. It never returns an error.
+func (s *Signer) Write(p []byte) (int, error) {
+	return s.h.Write(p)
+}
+
+// Sign returns the Ed25519ph signature, by privateKey, of everything written
+// to s so far. It does not change the state of s. Sign will panic if
+// len(privateKey) is not PrivateKeySize.
+func (s *Signer) Sign(privateKey PrivateKey) *[SignatureSize]byte {
+	out, err := ed25519.PrivateKey(privateKey).Sign(nil, s.h.Sum(nil), phOptions)
+	if err != nil {
+		panic(err)
+	}
+	sig := new([SignatureSize]byte)
+	copy(sig[:], out)
+	return sig
+}
+
+// A Verifier checks an Ed25519ph signature of a message that is written to it
+// in pieces. It is the counterpart to Signer, and is compatible with
+// libsodium's crypto_sign_init, crypto_sign_update and
+// crypto_sign_final_verify.
+type Verifier struct {
+	h hash.Hash
+}
+
+// NewVerifier returns a Verifier for a new message.
+func NewVerifier() *Verifier {
+	return &Verifier{h: sha512.New()}
+}
+
+// Write adds more of the message to be verified. It never returns an error.
+func (v *Verifier) Write(p []byte) (int, error) {
+	return v.h.Write(p)
+}
+
+// Verify checks that sig is a valid Ed25519ph signature, by publicKey, of
+// everything written to v so far. It returns an error if the signature is
+// invalid or publicKey has the wrong length. Verify does not change the state
+// of v.
+func (v *Verifier) Verify(sig *[SignatureSize]byte, publicKey PublicKey) error {
+	if err := checkPublicKey(publicKey); err != nil {
+		return err
+	}
+	if err := ed25519.VerifyWithOptions(ed25519.PublicKey(publicKey), v.h.Sum(nil), sig[:], phOptions); err != nil {
+		return errInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Signatures were generated with libsodium's crypto_sign_init,
+// crypto_sign_update and crypto_sign_final_create, for the key pair generated
+// by crypto_sign_seed_keypair from the seed 0x00..0x1f.
+var multipartTests = []struct {
+	parts []string
+	sig   string
+}{
+	{nil, "6232bae1c1460ffff7510997e0caed7d663b3969483a9471f6c37bbf41a4c1a762e2b85f47f4b308d79ab409b2bfe321b10671058a9d5cc9e52011ea46a9f802"},
+	{[]string{"Arbitrary data to hash"}, "70ae4215ec8bd3087ad0985e4d76a0ab9905ab3f9916dbca8312c2a747fff243e04eda4adc0ae0f5e5d53f0ae00d5c35f488285ad7f0e107518a52404a42ce04"},
+	{[]string{"Arbitrary data ", "to hash"}, "70ae4215ec8bd3087ad0985e4d76a0ab9905ab3f9916dbca8312c2a747fff243e04eda4adc0ae0f5e5d53f0ae00d5c35f488285ad7f0e107518a52404a42ce04"},
+}
+
+func seedPrivateKey() PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	return PrivateKey(ed25519.NewKeyFromSeed(seed))
+}
+
+func TestSigner(t *testing.T) {
+	priv := seedPrivateKey()
+	pub := priv.Public().(PublicKey)
+	if got := hex.EncodeToString(pub); got != "03a107bff3ce10be1d70dd18e74bc09967e4d6309ba50d5f1ddc8664125531b8" {
+		t.Fatalf("unexpected public key %s", got)
+	}
+	for i, tt := range multipartTests {
+		s := NewSigner()
+		v := NewVerifier()
+		for _, p := range tt.parts {
+			io.WriteString(s, p)
+			io.WriteString(v, p)
+		}
+		sig := s.Sign(priv)
+		if got := hex.EncodeToString(sig[:]); got != tt.sig {
+			t.Errorf("%d: Sign: got %s, want %s", i, got, tt.sig)
+		}
+		if err := v.Verify(sig, pub); err != nil {
+			t.Errorf("%d: Verify: %v", i, err)
+		}
+		io.WriteString(v, "more data")
+		if err := v.Verify(sig, pub); err == nil {
+			t.Errorf("%d: Verify accepted signature of a different message", i)
+		}
+	}
+}
+
+func TestSignerNotStandardEd25519(t *testing.T) {
+	priv := seedPrivateKey()
+	pub := priv.Public().(PublicKey)
+	message := []byte("Arbitrary data to hash")
+
+	s := NewSigner()
+	s.Write(message)
+	if err := VerifyDetached(message, s.Sign(priv), pub); err == nil {
+		t.Errorf("Ed25519ph signature verified as a standard Ed25519 signature")
+	}
+	v := NewVerifier()
+	v.Write(message)
+	if err := v.Verify(SignDetached(message, priv), pub); err == nil {
+		t.Errorf("standard Ed25519 signature verified as an Ed25519ph signature")
+	}
+}
+
+func TestCryptoSignerPrehashed(t *testing.T) {
+	priv := seedPrivateKey()
+	digest := sha512.Sum512([]byte(strings.Join(multipartTests[1].parts, "")))
+	for _, opts := range []crypto.SignerOpts{crypto.SHA512, &ed25519.Options{Hash: crypto.SHA512}} {
+		sig, err := crypto.Signer(priv).Sign(nil, digest[:], opts)
+		if err != nil {
+			t.Fatalf("Sign(%v): %v", opts, err)
+		}
+		if got := hex.EncodeToString(sig); got != multipartTests[1].sig {
+			t.Errorf("Sign(%v): got %s, want %s", opts, got, multipartTests[1].sig)
+		}
+	}
+	if _, err := crypto.Signer(priv).Sign(nil, digest[:32], crypto.SHA256); err == nil {
+		t.Errorf("Sign: expected error for SHA-256, got nil")
+	}
+}
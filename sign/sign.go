@@ -26,6 +26,8 @@ const (
 	PrivateKeySize = 64
 	// SignatureSize is the size, in bytes, of signatures generated and verified by this package.
 	SignatureSize = 64
+	// SeedSize is the size, in bytes, of private key seeds.
+	SeedSize = 32
 )
 
 // PublicKey is the type of Ed25519 public keys.
@@ -40,18 +42,42 @@ func (priv PrivateKey) Public() crypto.PublicKey {
 	return PublicKey(pub)
 }
 
+// Seed returns the private key seed corresponding to priv, the first SeedSize
+// bytes of the key. The key can be recreated by passing the seed to
+// KeyPairFromSeed.
+func (priv PrivateKey) Seed() []byte {
+	return ed25519.PrivateKey(priv).Seed()
+}
+
+// PublicKey returns the PublicKey corresponding to priv, the last
+// PublicKeySize bytes of the key.
+func (priv PrivateKey) PublicKey() PublicKey {
+	pub := make([]byte, PublicKeySize)
+	copy(pub, priv[SeedSize:])
+	return PublicKey(pub)
+}
+
 // Sign signs the given message with priv.
-// Ed25519 performs two passes over messages to be signed and therefore cannot
-// handle pre-hashed messages. Thus opts.HashFunc() must return zero to
-// indicate the message hasn't been hashed. This can be achieved by passing
-// crypto.Hash(0) as the value for opts.
+//
+// If opts.HashFunc() is zero, message is the full message to sign, and the
+// result is the signature followed by the message, the same as calling the
+// Sign function. This can be achieved by passing crypto.Hash(0) as the value
+// for opts.
+//
+// If opts.HashFunc() is crypto.SHA512, for example when opts is
+// &ed25519.Options{Hash: crypto.SHA512}, message must be the SHA-512 digest
+// of the message, and Sign returns only the 64-byte Ed25519ph signature
+// (RFC 8032, Section 5.1), the same signature produced by a Signer. Any other
+// hash function is rejected.
 func (priv PrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
-	if opts.HashFunc() != crypto.Hash(0) {
-		return nil, errors.New("sign: cannot sign hashed message")
+	switch opts.HashFunc() {
+	case crypto.Hash(0):
+		return Sign(message, priv), nil
+	case crypto.SHA512:
+		return ed25519.PrivateKey(priv).Sign(rand, message, opts)
+	default:
+		return nil, errors.New("sign: expected opts.HashFunc() zero (unhashed message, for standard Ed25519) or SHA-512 (for Ed25519ph)")
 	}
-
-	out := Sign(message, priv)
-	return out[:], nil
 }
 
 // Keypair generates a public/private key pair using entropy from rand.
@@ -64,6 +90,14 @@ func Keypair(rand io.Reader) (publicKey PublicKey, privateKey PrivateKey, err er
 	return PublicKey(public), PrivateKey(private), nil
 }
 
+// KeyPairFromSeed deterministically derives a public/private key pair from
+// seed, matching libsodium's crypto_sign_seed_keypair. The seed must be kept
+// as secret as the private key.
+func KeyPairFromSeed(seed *[SeedSize]byte) (publicKey PublicKey, privateKey PrivateKey) {
+	private := ed25519.NewKeyFromSeed(seed[:])
+	return PrivateKey(private).PublicKey(), PrivateKey(private)
+}
+
 // Sign signs the message with privateKey. The first SignatureSize bytes of the
 // response will be the signature; the rest will be the message. It will panic
 // if len(privateKey) is not PrivateKeySize.
@@ -75,6 +109,15 @@ func Sign(message []byte, privateKey PrivateKey) []byte {
 	return response
 }
 
+// SignDetached signs the message with privateKey and returns the signature
+// alone, without the message. It will panic if len(privateKey) is not
+// PrivateKeySize.
+func SignDetached(message []byte, privateKey PrivateKey) *[SignatureSize]byte {
+	sig := new([SignatureSize]byte)
+	copy(sig[:], ed25519.Sign(ed25519.PrivateKey(privateKey), message))
+	return sig
+}
+
 // Verify uses key to report whether signature is a valid signature of message.
 // The first SignatureSize bytes of signature should be the signature; the
 // remaining bytes are the message to verify.
@@ -101,3 +144,47 @@ func Verify(sig []byte, publicKey PublicKey) bool {
 
 	return ed25519.Verify(ed25519.PublicKey(publicKey), msg, sig)
 }
+
+var (
+	errInvalidSignature = errors.New("sign: invalid signature")
+	errTooShort         = errors.New("sign: signed message too short")
+)
+
+func checkPublicKey(publicKey PublicKey) error {
+	if l := len(publicKey); l != PublicKeySize {
+		return errors.New("sign: bad public key length: " + strconv.Itoa(l))
+	}
+	return nil
+}
+
+// VerifyDetached checks that sig is a valid signature of message by
+// publicKey, as produced by SignDetached. It returns an error if the
+// signature is invalid or publicKey has the wrong length.
+func VerifyDetached(message []byte, sig *[SignatureSize]byte, publicKey PublicKey) error {
+	if err := checkPublicKey(publicKey); err != nil {
+		return err
+	}
+	if sig[63]&224 != 0 || !ed25519.Verify(ed25519.PublicKey(publicKey), message, sig[:]) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// Open verifies a signed message produced by Sign and returns a copy of the
+// message without the signature. If the signature is invalid, Open returns an
+// error and no message, so callers can't accidentally use unverified data.
+func Open(signedMessage []byte, publicKey PublicKey) ([]byte, error) {
+	if err := checkPublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	if len(signedMessage) < SignatureSize {
+		return nil, errTooShort
+	}
+	var sig [SignatureSize]byte
+	copy(sig[:], signedMessage)
+	message := signedMessage[SignatureSize:]
+	if err := VerifyDetached(message, &sig, publicKey); err != nil {
+		return nil, err
+	}
+	return append([]byte{}, message...), nil
+}
@@ -187,3 +187,94 @@ func BenchmarkVerification(b *testing.B) {
 		Verify(signature, pub)
 	}
 }
+
+func TestSignVerifyDetached(t *testing.T) {
+	var zero zeroReader
+	public, private, _ := Keypair(zero)
+
+	message := []byte("test message")
+	sig := SignDetached(message, private)
+	signed := Sign(message, private)
+	if !bytes.Equal(sig[:], signed[:SignatureSize]) {
+		t.Errorf("SignDetached: signature does not match Sign")
+	}
+	if err := VerifyDetached(message, sig, public); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifyDetached([]byte("wrong message"), sig, public); err == nil {
+		t.Errorf("signature of different message accepted")
+	}
+	sig[0] ^= 0x01
+	if err := VerifyDetached(message, sig, public); err == nil {
+		t.Errorf("corrupted signature accepted")
+	}
+	sig[0] ^= 0x01
+	if err := VerifyDetached(message, sig, public[:31]); err == nil {
+		t.Errorf("expected error for short public key, got nil")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	var zero zeroReader
+	public, private, _ := Keypair(zero)
+
+	message := []byte("test message")
+	signed := Sign(message, private)
+	opened, err := Open(signed, public)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !bytes.Equal(opened, message) {
+		t.Errorf("Open: got %q, want %q", opened, message)
+	}
+	opened[0] ^= 0x01
+	if !bytes.Equal(signed[SignatureSize:], message) {
+		t.Errorf("Open: returned message aliases the signed message")
+	}
+
+	for _, i := range []int{0, SignatureSize - 1, SignatureSize, len(signed) - 1} {
+		signed[i] ^= 0x01
+		if m, err := Open(signed, public); err == nil || m != nil {
+			t.Errorf("Open: accepted signed message with byte %d corrupted", i)
+		}
+		signed[i] ^= 0x01
+	}
+	if _, err := Open(signed[:SignatureSize-1], public); err == nil {
+		t.Errorf("Open: accepted short signed message")
+	}
+	if _, err := Open(signed, public[:31]); err == nil {
+		t.Errorf("Open: expected error for short public key, got nil")
+	}
+}
+
+func TestKeyPairFromSeed(t *testing.T) {
+	var seed [SeedSize]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	publicKey, privateKey := KeyPairFromSeed(&seed)
+	// Generated with libsodium's crypto_sign_seed_keypair.
+	const wantPublic = "03a107bff3ce10be1d70dd18e74bc09967e4d6309ba50d5f1ddc8664125531b8"
+	if got := hex.EncodeToString(publicKey); got != wantPublic {
+		t.Errorf("public key: got %s, want %s", got, wantPublic)
+	}
+	if got, want := hex.EncodeToString(privateKey), hex.EncodeToString(seed[:])+wantPublic; got != want {
+		t.Errorf("private key: got %s, want %s", got, want)
+	}
+	if !bytes.Equal(privateKey.Seed(), seed[:]) {
+		t.Errorf("Seed: got %x, want %x", privateKey.Seed(), seed)
+	}
+	if !bytes.Equal(privateKey.PublicKey(), publicKey) {
+		t.Errorf("PublicKey: got %x, want %x", privateKey.PublicKey(), publicKey)
+	}
+
+	pub, priv, err := Keypair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(seed[:], priv.Seed())
+	pub2, priv2 := KeyPairFromSeed(&seed)
+	if !bytes.Equal(pub, pub2) || !bytes.Equal(priv, priv2) {
+		t.Errorf("KeyPairFromSeed(priv.Seed()) did not recreate the key pair")
+	}
+}
@@ -0,0 +1,86 @@
+// Package chacha20 implements the original ChaCha20 stream cipher, designed by
+// Daniel J. Bernstein, with an 8-byte nonce and a 64-bit block counter. It is
+// interoperable with libsodium's crypto_stream_chacha20.
+//
+// This is not the ChaCha20 specified in RFC 8439, which has a 12-byte nonce
+// and a 32-bit block counter; that version is in the stream/chacha20ietf
+// package.
+//
+// An 8-byte nonce is too short to generate at random without risking a
+// collision, so nonces must come from a counter or another source that never
+// repeats for a given key. Use the stream/xchacha20 package, which takes a
+// 24-byte nonce, if you need random nonces.
+//
+// This package does not authenticate the data it encrypts. To detect
+// tampering, use the secretbox/xchacha20poly1305 package instead.
+package chacha20 // import "github.com/kevinburke/nacl/stream/chacha20"
+
+import (
+	"encoding/binary"
+
+	"github.com/kevinburke/nacl"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// KeySize is the size, in bytes, of a ChaCha20 key.
+	KeySize = 32
+	// NonceSize is the size, in bytes, of a ChaCha20 nonce.
+	NonceSize = 8
+	// BlockSize is the size, in bytes, of a keystream block. Block counters
+	// passed to XORIC count blocks of this size.
+	BlockSize = 64
+)
+
+// Stream returns the first l bytes of the ChaCha20 keystream for nonce and
+// key.
+func Stream(l int, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	out := make([]byte, l)
+	xorKeyStream(out, out, nonce, 0, key)
+	return out
+}
+
+// XOR encrypts or decrypts message by XORing it with the ChaCha20 keystream
+// for nonce and key, and returns the result. It is the caller's
+// responsibility to ensure that a nonce is never reused with the same key.
+func XOR(message []byte, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	return XORIC(message, nonce, 0, key)
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning, the same as libsodium's crypto_stream_chacha20_xor_ic.
+// Byte i of message is XORed with byte counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce *[NonceSize]byte, counter uint64, key nacl.Key) []byte {
+	out := make([]byte, len(message))
+	xorKeyStream(out, message, nonce, counter, key)
+	return out
+}
+
+// xorKeyStream uses golang.org/x/crypto/chacha20, which implements the RFC
+// 8439 version of ChaCha20. The two versions lay out the same 16 bytes
+// differently: this one as a 64-bit counter and 8-byte nonce, RFC 8439 as a
+// 32-bit counter and 12-byte nonce. So the top half of our counter becomes
+// the first 4 bytes of the RFC 8439 nonce, and whenever the bottom half
+// wraps around we carry into it by starting a new cipher.
+func xorKeyStream(out, in []byte, nonce *[NonceSize]byte, counter uint64, key nacl.Key) {
+	var ietfNonce [chacha20.NonceSize]byte
+	copy(ietfNonce[4:], nonce[:])
+	for len(in) > 0 {
+		binary.LittleEndian.PutUint32(ietfNonce[:4], uint32(counter>>32))
+		c, err := chacha20.NewUnauthenticatedCipher(key[:], ietfNonce[:])
+		if err != nil {
+			panic(err)
+		}
+		c.SetCounter(uint32(counter))
+
+		// The number of blocks before the bottom half of the counter wraps.
+		blocks := 1<<32 - uint64(uint32(counter))
+		n := len(in)
+		if uint64(n) > blocks*BlockSize {
+			n = int(blocks * BlockSize)
+		}
+		c.XORKeyStream(out[:n], in[:n])
+		in, out = in[n:], out[n:]
+		counter += blocks
+	}
+}
@@ -0,0 +1,80 @@
+package chacha20
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Generated with libsodium's crypto_stream_chacha20_xor_ic, with the
+// nonce 0x00..0x07, the key 0x20..0x3f and the message 0x00..0x63.
+var xorICTests = []struct {
+	counter uint64
+	out     string
+}{
+	{0, "c8f99a2ac550ed3188499ba93505aa82305c2460f712fda17a57afec214c8e7438bd057d230430111acd38733dd15974e865afe369b1aed1ca615e8a3c965465bf251af6e6317c470b8264c5c93fcb08618bde2815924d56c9f7c085467e15c4c7d015ee"},
+	{1, "ff655ab6a6713c074bc22485897f8b4821cb9e6855d20d1689b780c5063e5584879055aecea09897f66c45dc6175a4f5082d598177e7e7a399928914b3e9025015380a485ed89888d1dd558819249309f500755b0b2055f88a5b85f170dd6b2df14713ff"},
+	// The counter is 64 bits, and carries into its upper half.
+	{0xffffffff, "66cc51e1a598065190221f99eb3be760826b5a6844a2609bae5a42f830e9a3fe4903fc376e0a01ff0e589c55b99b3ff153f7b1a93bfd2374dab25e18c6deba613466ed88023133199bbcd29489505e7b628228cd04537eb9ac7e7f0abf233ccd4d1f909f"},
+}
+
+func TestXORIC(t *testing.T) {
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	for _, tt := range xorICTests {
+		if got := hex.EncodeToString(XORIC(message, &nonce, tt.counter, &key)); got != tt.out {
+			t.Errorf("XORIC(%#x): got %s, want %s", tt.counter, got, tt.out)
+		}
+	}
+
+	stream := Stream(4*BlockSize, &nonce, &key)
+	ciphertext := XOR(message, &nonce, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[i] {
+			t.Fatalf("XOR: byte %d is not message XOR Stream", i)
+		}
+	}
+	ciphertext = XORIC(message, &nonce, 2, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[2*BlockSize+i] {
+			t.Fatalf("XORIC(2): byte %d is not message XOR Stream", i)
+		}
+	}
+}
+
+func TestStrombergson(t *testing.T) {
+	// The first block of keystream for TC1 and TC8 in
+	// draft-strombergson-chacha-test-vectors, with 20 rounds.
+	tests := []struct {
+		key, nonce, want string
+	}{
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000",
+			"76b8e0ada0f13d90405d6ae55386bd28bdd219b8a08ded1aa836efcc8b770dc7da41597c5157488d7724e03fb8d84a376a43b8f41518a11cc387b669b2ee6586",
+		},
+		{
+			"c46ec1b18ce8a878725a37e780dfb7351f68ed2e194c79fbc6aebee1a667975d",
+			"1ada31d5cf688221",
+			"f63a89b75c2271f9368816542ba52f06ed49241792302b00b5e8f80ae9a473afc25b218f519af0fdd406362e8d69de7f54c604a6e00f353f110f771bdca8ab92",
+		},
+	}
+	for i, tt := range tests {
+		var key [KeySize]byte
+		var nonce [NonceSize]byte
+		hex.Decode(key[:], []byte(tt.key))
+		hex.Decode(nonce[:], []byte(tt.nonce))
+		if got := hex.EncodeToString(Stream(BlockSize, &nonce, &key)); got != tt.want {
+			t.Errorf("%d: got %s, want %s", i, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package chacha20ietf implements the ChaCha20 stream cipher as specified in
+// RFC 8439, with a 12-byte nonce and a 32-bit block counter. It is
+// interoperable with libsodium's crypto_stream_chacha20_ietf.
+//
+// A 12-byte nonce is too short to generate at random for a large number of
+// messages under the same key. Use the stream/xchacha20 package, which takes
+// a 24-byte nonce, if you need random nonces.
+//
+// The 32-bit block counter limits the keystream for a nonce and key to 256
+// GiB. XOR and XORIC panic if message would need more keystream than that.
+//
+// This package does not authenticate the data it encrypts. To detect
+// tampering, use the secretbox/xchacha20poly1305 package instead.
+package chacha20ietf // import "github.com/kevinburke/nacl/stream/chacha20ietf"
+
+import (
+	"github.com/kevinburke/nacl"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// KeySize is the size, in bytes, of a ChaCha20 key.
+	KeySize = 32
+	// NonceSize is the size, in bytes, of a ChaCha20 nonce.
+	NonceSize = 12
+	// BlockSize is the size, in bytes, of a keystream block. Block counters
+	// passed to XORIC count blocks of this size.
+	BlockSize = 64
+)
+
+// Stream returns the first l bytes of the ChaCha20 keystream for nonce and
+// key.
+func Stream(l int, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	out := make([]byte, l)
+	xorKeyStream(out, out, nonce, 0, key)
+	return out
+}
+
+// XOR encrypts or decrypts message by XORing it with the ChaCha20 keystream
+// for nonce and key, and returns the result. It is the caller's
+// responsibility to ensure that a nonce is never reused with the same key.
+func XOR(message []byte, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	return XORIC(message, nonce, 0, key)
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning, the same as libsodium's
+// crypto_stream_chacha20_ietf_xor_ic. Byte i of message is XORed with byte
+// counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce *[NonceSize]byte, counter uint32, key nacl.Key) []byte {
+	out := make([]byte, len(message))
+	xorKeyStream(out, message, nonce, counter, key)
+	return out
+}
+
+func xorKeyStream(out, in []byte, nonce *[NonceSize]byte, counter uint32, key nacl.Key) {
+	c, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		panic(err)
+	}
+	c.SetCounter(counter)
+	c.XORKeyStream(out, in)
+}
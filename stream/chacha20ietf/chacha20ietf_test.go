@@ -0,0 +1,65 @@
+package chacha20ietf
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Generated with libsodium's crypto_stream_chacha20_ietf_xor_ic, with the
+// nonce 0x00..0x0b, the key 0x20..0x3f and the message 0x00..0x63.
+var xorICTests = []struct {
+	counter uint32
+	out     string
+}{
+	{0, "38a59d88ddc90d18a3eef88dfbfc9b8bc484be18ca0edbd0c6393fd4af31fb64ab8655b6be8b318c714774509018d013901c0a40f4c4fd7e75093a578d05cdba907b8b7ce56d08919243f1a7f1e5d91ab1e0062a21fb782a6e5291c9e284aa55a9a4676b"},
+	{1, "d03bcb3ca52d48d1d203b1e7b1a5995af1a0466a61bb386a2e12d189a2c4ea15e9e4272b0868e6b95b9dfa135a77dba64bbc83e266273d5e74ae3bf3924425f09126db46d64b9aefe52f31b87b2977061cf2e067125f79479729a566d46351eeb575964a"},
+	{5, "799ba726a6ade9eece48b2e84592c1ded0e2db8052ce35d269a73506de0feacb66a9acdd06261cdf3bb45050d532997e391723ff5ddfa93747608e47339660b8f75028a05bac95a0cffb0530a1bcc1546ebdcb9fc3ddf2cdb75f19b4edf00e70ce33d4d1"},
+}
+
+func TestXORIC(t *testing.T) {
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	for _, tt := range xorICTests {
+		if got := hex.EncodeToString(XORIC(message, &nonce, tt.counter, &key)); got != tt.out {
+			t.Errorf("XORIC(%#x): got %s, want %s", tt.counter, got, tt.out)
+		}
+	}
+
+	stream := Stream(4*BlockSize, &nonce, &key)
+	ciphertext := XOR(message, &nonce, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[i] {
+			t.Fatalf("XOR: byte %d is not message XOR Stream", i)
+		}
+	}
+	ciphertext = XORIC(message, &nonce, 2, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[2*BlockSize+i] {
+			t.Fatalf("XORIC(2): byte %d is not message XOR Stream", i)
+		}
+	}
+}
+
+func TestRFC8439(t *testing.T) {
+	// RFC 8439, Section 2.4.2.
+	var key [KeySize]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := &[NonceSize]byte{0, 0, 0, 0, 0, 0, 0, 0x4a, 0, 0, 0, 0}
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	const want = "6e2e359a2568f98041ba0728dd0d6981e97e7aec1d4360c20a27afccfd9fae0bf91b65c5524733ab8f593dabcd62b3571639d624e65152ab8f530c359f0861d807ca0dbf500d6a6156a38e088a22b65e52bc514d16ccf806818ce91ab77937365af90bbf74a35be6b40b8eedf2785e42874d"
+	if got := hex.EncodeToString(XORIC(plaintext, nonce, 1, &key)); got != want {
+		t.Errorf("XORIC: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/internal/subtle"
+	"golang.org/x/crypto/salsa20/salsa"
+)
+
+// BlockSize is the size, in bytes, of an XSalsa20 keystream block. Block
+// counters passed to SetCounter and XORIC count blocks of this size.
+const BlockSize = 64
+
+// Cipher is an XSalsa20 keystream that can be repositioned with SetCounter
+// and Seek, so that part of a large message can be encrypted or decrypted
+// without generating the keystream that comes before it. It implements
+// cipher.Stream.
+//
+// Like XOR, Cipher does not authenticate the data it encrypts.
+type Cipher struct {
+	subKey  nacl.Key
+	counter [16]byte // the last 8 bytes of the nonce, then the block counter
+
+	// buf holds the keystream block before counter. The bytes from used
+	// onwards have not yet been XORed with any input.
+	buf  [BlockSize]byte
+	used int
+}
+
+var _ cipher.Stream = (*Cipher)(nil)
+
+// NewCipher returns a Cipher that produces the same keystream as XOR for the
+// given nonce and key, starting at the beginning of the stream.
+func NewCipher(nonce nacl.Nonce, key nacl.Key) *Cipher {
+	subKey, counter := nacl.Setup(nonce, key)
+	return &Cipher{subKey: subKey, counter: *counter, used: BlockSize}
+}
+
+// SetCounter moves the keystream to the start of block number counter, so
+// that the next byte XORed is byte counter*BlockSize of the stream.
+func (c *Cipher) SetCounter(counter uint64) {
+	binary.LittleEndian.PutUint64(c.counter[8:], counter)
+	c.used = BlockSize
+}
+
+// Seek moves the keystream to the given byte offset, so that the next byte
+// XORed is byte offset of the stream.
+func (c *Cipher) Seek(offset uint64) {
+	c.SetCounter(offset / BlockSize)
+	if rem := int(offset % BlockSize); rem > 0 {
+		c.nextBlock()
+		c.used = rem
+	}
+}
+
+// nextBlock fills buf with the keystream block at counter and advances
+// counter.
+func (c *Cipher) nextBlock() {
+	c.buf = [BlockSize]byte{}
+	salsa.XORKeyStream(c.buf[:], c.buf[:], &c.counter, c.subKey)
+	c.advance(1)
+	c.used = 0
+}
+
+// advance moves counter forward by n blocks.
+func (c *Cipher) advance(n uint64) {
+	counter := binary.LittleEndian.Uint64(c.counter[8:])
+	binary.LittleEndian.PutUint64(c.counter[8:], counter+n)
+}
+
+// XORKeyStream XORs each byte in src with a byte from the keystream and
+// writes the result to dst. dst and src must overlap entirely or not at all,
+// and dst must be at least as long as src.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("stream: output smaller than input")
+	}
+	dst = dst[:len(src)]
+	if subtle.InexactOverlap(dst, src) {
+		panic("stream: invalid buffer overlap")
+	}
+
+	// Use up any keystream left over from the last call.
+	if c.used < BlockSize {
+		n := min(len(src), BlockSize-c.used)
+		for i := range n {
+			dst[i] = src[i] ^ c.buf[c.used+i]
+		}
+		c.used += n
+		dst, src = dst[n:], src[n:]
+	}
+
+	if full := len(src) - len(src)%BlockSize; full > 0 {
+		salsa.XORKeyStream(dst[:full], src[:full], &c.counter, c.subKey)
+		c.advance(uint64(full / BlockSize))
+		dst, src = dst[full:], src[full:]
+	}
+
+	if len(src) > 0 {
+		c.nextBlock()
+		for i := range src {
+			dst[i] = src[i] ^ c.buf[i]
+		}
+		c.used = len(src)
+	}
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning, the same as libsodium's
+// crypto_stream_xsalsa20_xor_ic. Byte i of message is XORed with byte
+// counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce nacl.Nonce, counter uint64, key nacl.Key) []byte {
+	c := NewCipher(nonce, key)
+	c.SetCounter(counter)
+	out := make([]byte, len(message))
+	c.XORKeyStream(out, message)
+	return out
+}
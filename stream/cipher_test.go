@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Generated with libsodium's crypto_stream_xsalsa20_xor_ic, with the nonce
+// 0x00..0x17, the key 0x20..0x3f and the message 0x00..0x63.
+var xorICTests = []struct {
+	counter uint64
+	out     string
+}{
+	{0, "a2f7316d4d29783bcae7e46fe15d4bf807d19b29825cbe147c4c8be7ec2246058c3a2bd920099842ab7051aa9844e822ed8e2069875cbd7d2c788333be1ccf4403b8030fbf410ea44923ad59f9daa931232e0e636e009d4ffedf1c654f91cc923872fd9e"},
+	{1, "43f8434fff014ee40963ed19b99ae971636e4e232e40dd0fbe9f5c250fd18cd27832bdde31d71acdfbf2f8dc69cf3247fa34747392b4ac7a0477144fa2a5a8f83706b0d2684d5b5e1bf82783d97709f2df183f1eb38eeafe7cb6ed6ea83e959877348577"},
+	{7, "5221fbbab31d312f32f22e2b4ab6a521ac13399a497507c83f6ce4c623c7ef42ce520f0dbd07898422a07933fe0607557fdd314522f71b16858acfedf81627cbe83e1c633f0e78841d6867fe045abfca200ddc6b28913b6bd5b7947eb941f08e2b973aac"},
+	// The block counter is 64 bits, and carries into its upper half.
+	{0xffffffff, "b192b023c8bf28c36c5a5cfa902113d900d7b14520a3da205c730549045d7d65db09e05b6a72213bb58e02e3543ffe1ae30523c22a847dfe85073efbdfc9bf96b0a3eb6dcd254eff7a8d75fb850d93cc63804b0e51e30da1b9fc98f889297e044e713749"},
+	{0x100000000, "f0e3ab2d8d650ebf3acd35bbc54dd38c23c00b4e11a34de1f9bcd8b8c9693e440e3177093be0765f764a62618367cf91700efebc5b4e28e8c6477d8d22c6375ed60e2e7210bd60247186da9015ca8811b44a9d79824852c9d863e43fd60b1bf691680573"},
+}
+
+func TestXORIC(t *testing.T) {
+	var nonce [24]byte
+	var key [32]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	for _, tt := range xorICTests {
+		if got := hex.EncodeToString(XORIC(message, &nonce, tt.counter, &key)); got != tt.out {
+			t.Errorf("XORIC(%#x): got %s, want %s", tt.counter, got, tt.out)
+		}
+	}
+	if got := XORIC(message, &nonce, 0, &key); !bytes.Equal(got, XOR(message, &nonce, &key)) {
+		t.Errorf("XORIC with counter 0 does not match XOR")
+	}
+}
+
+func TestCipher(t *testing.T) {
+	var nonce [24]byte
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	message := make([]byte, 1000)
+	for i := range message {
+		message[i] = byte(i * 7)
+	}
+	want := XOR(message, &nonce, &key)
+
+	// Encrypt the message in pieces of every size up to a few blocks.
+	for size := 1; size <= 3*BlockSize+1; size++ {
+		c := NewCipher(&nonce, &key)
+		got := make([]byte, len(message))
+		for i := 0; i < len(message); i += size {
+			end := min(i+size, len(message))
+			c.XORKeyStream(got[i:end], message[i:end])
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("size %d: XORKeyStream does not match XOR", size)
+		}
+	}
+
+	// Encrypt in place.
+	got := append([]byte{}, message...)
+	NewCipher(&nonce, &key).XORKeyStream(got, got)
+	if !bytes.Equal(got, want) {
+		t.Errorf("in place XORKeyStream does not match XOR")
+	}
+
+	c := NewCipher(&nonce, &key)
+	for _, offset := range []uint64{0, 1, 63, 64, 65, 500, 999, 63, 0} {
+		c.Seek(offset)
+		got := make([]byte, len(message)-int(offset))
+		c.XORKeyStream(got, message[offset:])
+		if !bytes.Equal(got, want[offset:]) {
+			t.Errorf("Seek(%d): XORKeyStream does not match XOR", offset)
+		}
+	}
+	for _, counter := range []uint64{0, 1, 15, 2} {
+		c.SetCounter(counter)
+		offset := counter * BlockSize
+		got := make([]byte, len(message)-int(offset))
+		c.XORKeyStream(got, message[offset:])
+		if !bytes.Equal(got, want[offset:]) {
+			t.Errorf("SetCounter(%d): XORKeyStream does not match XOR", counter)
+		}
+	}
+}
+
+func TestCipherPanics(t *testing.T) {
+	var nonce [24]byte
+	var key [32]byte
+	c := NewCipher(&nonce, &key)
+	buf := make([]byte, 10)
+	for name, f := range map[string]func(){
+		"short output": func() { c.XORKeyStream(buf[:5], buf) },
+		"overlap":      func() { c.XORKeyStream(buf[1:], buf[:9]) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,51 @@
+// Package salsa20 implements the Salsa20 stream cipher, designed by Daniel J.
+// Bernstein, with an 8-byte nonce. It is interoperable with libsodium's
+// crypto_stream_salsa20.
+//
+// An 8-byte nonce is too short to generate at random without risking a
+// collision, so nonces must come from a counter or another source that never
+// repeats for a given key. Use the stream package, whose XSalsa20 cipher takes
+// a 24-byte nonce, if you need random nonces.
+//
+// This package does not authenticate the data it encrypts. To detect
+// tampering, use the secretbox package instead.
+package salsa20 // import "github.com/kevinburke/nacl/stream/salsa20"
+
+import (
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/internal/salsa"
+)
+
+const (
+	// KeySize is the size, in bytes, of a Salsa20 key.
+	KeySize = 32
+	// NonceSize is the size, in bytes, of a Salsa20 nonce.
+	NonceSize = salsa.NonceSize
+	// BlockSize is the size, in bytes, of a keystream block. Block counters
+	// passed to XORIC count blocks of this size.
+	BlockSize = salsa.BlockSize
+)
+
+// Stream returns the first l bytes of the Salsa20 keystream for nonce and
+// key.
+func Stream(l int, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	out := make([]byte, l)
+	salsa.XORKeyStream(out, out, nonce, 0, key, 20)
+	return out
+}
+
+// XOR encrypts or decrypts message by XORing it with the Salsa20 keystream
+// for nonce and key, and returns the result. It is the caller's
+// responsibility to ensure that a nonce is never reused with the same key.
+func XOR(message []byte, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	return XORIC(message, nonce, 0, key)
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning, the same as libsodium's crypto_stream_salsa20_xor_ic.
+// Byte i of message is XORed with byte counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce *[NonceSize]byte, counter uint64, key nacl.Key) []byte {
+	out := make([]byte, len(message))
+	salsa.XORKeyStream(out, message, nonce, counter, key, 20)
+	return out
+}
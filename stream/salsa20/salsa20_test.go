@@ -0,0 +1,99 @@
+package salsa20
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// secondKey and nonceSuffix are from NaCl's tests/stream2.c: the HSalsa20
+// subkey and the last 8 bytes of the XSalsa20 nonce in the stream package's
+// TestStream1, so the keystream is the same.
+var secondKey = &[KeySize]byte{
+	0xdc, 0x90, 0x8d, 0xda, 0x0b, 0x93, 0x44, 0xa9,
+	0x53, 0x62, 0x9b, 0x73, 0x38, 0x20, 0x77, 0x88,
+	0x80, 0xf3, 0xce, 0xb4, 0x21, 0xbb, 0x61, 0xb9,
+	0x1c, 0xbd, 0x4c, 0x3e, 0x66, 0x25, 0x6c, 0xe4,
+}
+
+var nonceSuffix = &[NonceSize]byte{
+	0x82, 0x19, 0xe0, 0x03, 0x6b, 0x7a, 0x0b, 0x37,
+}
+
+func TestStream2(t *testing.T) {
+	const want = "662b9d0e3463029156069b12f918691a98f7dfb2ca0393c96bbfc6b1fbd630a2"
+	out := Stream(4194304, nonceSuffix, secondKey)
+	if got := sha256.Sum256(out); hex.EncodeToString(got[:]) != want {
+		t.Errorf("Stream: got SHA-256 %x, want %s", got, want)
+	}
+}
+
+func TestXORICCarry(t *testing.T) {
+	// Generated with libsodium's crypto_stream_salsa20_xor_ic, with the nonce
+	// 0x00..0x07, the key 0x20..0x3f, the message 0x00..0x63 and the counter
+	// 0xffffffff, so the block counter carries into its upper half.
+	const want = "78609b03d9b32f46af2849135ab34756511eb10470acdb3f89344a52bc83d97e2a8bef3e8eba6af9fff4a69c31d23df3e30d931dfb8b1a0027185e94148aec279575e9d9c132a2c9e50cd4c5606ea679b0d5e939e9d78dd69f6dd364818dd583b81dcf1c"
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	if got := hex.EncodeToString(XORIC(message, &nonce, 0xffffffff, &key)); got != want {
+		t.Errorf("XORIC: got %s, want %s", got, want)
+	}
+}
+
+func TestESTREAM(t *testing.T) {
+	// The first and eighth blocks of keystream for eSTREAM's 256-bit key
+	// Set 1, vector 0: the key 0x80 0x00 ... 0x00 and an all zero nonce.
+	// From the eSTREAM test vectors.
+	const (
+		block0 = "e3be8fdd8beca2e3ea8ef9475b29a6e7003951e1097a5c38d23b7a5fad9f6844b22c97559e2723c7cbbd3fe4fc8d9a0744652a83e72a9c461876af4d7ef1a117"
+		block7 = "696afcfd0cddcc83c7e77f11a649d79acdc3354e9635ff137e929933a0bd6f5377efa105a3a4266b7c0d089d08f1e855cc32b15b93784a36e56a76cc64bc8477"
+	)
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	key[0] = 0x80
+	stream := Stream(8*BlockSize, &nonce, &key)
+	if got := hex.EncodeToString(stream[:BlockSize]); got != block0 {
+		t.Errorf("Stream block 0: got %s, want %s", got, block0)
+	}
+	if got := hex.EncodeToString(stream[7*BlockSize:]); got != block7 {
+		t.Errorf("Stream block 7: got %s, want %s", got, block7)
+	}
+	if got := hex.EncodeToString(XORIC(make([]byte, BlockSize), &nonce, 7, &key)); got != block7 {
+		t.Errorf("XORIC(7): got %s, want %s", got, block7)
+	}
+	if got := XORIC(make([]byte, 3*BlockSize+5), &nonce, 2, &key); !bytes.Equal(got, stream[2*BlockSize:5*BlockSize+5]) {
+		t.Errorf("XORIC(2) does not continue Stream")
+	}
+}
+
+func TestXOR(t *testing.T) {
+	// Generated with libsodium's crypto_stream_salsa20_xor, with the nonce
+	// 0x00..0x07, the key 0x20..0x3f and the message 0x00..0x63.
+	const want = "7ead58d8087dccba517401e9f39be3b21b40f93f9494df150ea6dfa9d4ad0809b78c66988241a9250151911b2095a55ace27991ad1935fa1188402b9ff9c12490ec548b9878fa5b908702b996a0e3119b27fc22c91d3078ba9518f6c2c3e74974e702c95"
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	if got := hex.EncodeToString(XOR(message, &nonce, &key)); got != want {
+		t.Errorf("XOR: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package salsa2012
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestESTREAM(t *testing.T) {
+	// The first and eighth blocks of keystream for eSTREAM's 256-bit key
+	// Set 1, vector 0: the key 0x80 0x00 ... 0x00 and an all zero nonce.
+	// Generated with libsodium's crypto_stream_salsa2012.
+	const (
+		block0 = "afe411ed1c4e07e4d0cde3b33e31ec190fa4cc796a58bafb848ead8d07d02cd2d4b6f9f30cb0b57007e3733895cc8d1060107975acaeeb689b6cf614ab64a3d6"
+		block7 = "87a5191ec2e3c9049fa524cd8673e0677c77adcf8ab5328fd828c4acb3eccca549adeda04872518ecdf874adcb2420c7bd1ccfe561b074080224fa7176f0cb5f"
+	)
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	key[0] = 0x80
+	stream := Stream(8*BlockSize, &nonce, &key)
+	if got := hex.EncodeToString(stream[:BlockSize]); got != block0 {
+		t.Errorf("Stream block 0: got %s, want %s", got, block0)
+	}
+	if got := hex.EncodeToString(stream[7*BlockSize:]); got != block7 {
+		t.Errorf("Stream block 7: got %s, want %s", got, block7)
+	}
+	if got := hex.EncodeToString(XORIC(make([]byte, BlockSize), &nonce, 7, &key)); got != block7 {
+		t.Errorf("XORIC(7): got %s, want %s", got, block7)
+	}
+	if got := XORIC(make([]byte, 3*BlockSize+5), &nonce, 2, &key); !bytes.Equal(got, stream[2*BlockSize:5*BlockSize+5]) {
+		t.Errorf("XORIC(2) does not continue Stream")
+	}
+}
+
+func TestXOR(t *testing.T) {
+	// Generated with libsodium's crypto_stream_salsa2012_xor, with the nonce
+	// 0x00..0x07, the key 0x20..0x3f and the message 0x00..0x63.
+	const want = "46ebafa9218b06ad204f3e9783857c3b5f271730dd54e11dc9d71f57a09ebeda7bfde2a3cdb752a426e97af18d33c18a00980ebec22f865c1ed41e15c05f8b3a2196f593a64b57a706ef79f46f61151c1b2b018686f0854a71b3b7a96f6e7cfb2725c3cd"
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	if got := hex.EncodeToString(XOR(message, &nonce, &key)); got != want {
+		t.Errorf("XOR: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Package salsa208 implements the Salsa20/8 stream cipher, the 8 round version
+// of Salsa20, with an 8-byte nonce. It is interoperable with libsodium's
+// crypto_stream_salsa208.
+//
+// An 8-byte nonce is too short to generate at random without risking a
+// collision, so nonces must come from a counter or another source that never
+// repeats for a given key.
+//
+// Salsa20/8 is faster than Salsa20, with a much smaller security margin.
+// Prefer the full 20 round ciphers in the stream and stream/salsa20 packages
+// unless you need to interoperate with Salsa20/8.
+//
+// This package does not authenticate the data it encrypts. To detect
+// tampering, use the secretbox package instead.
+package salsa208 // import "github.com/kevinburke/nacl/stream/salsa208"
+
+import (
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/internal/salsa"
+)
+
+const (
+	// KeySize is the size, in bytes, of a Salsa20/8 key.
+	KeySize = 32
+	// NonceSize is the size, in bytes, of a Salsa20/8 nonce.
+	NonceSize = salsa.NonceSize
+	// BlockSize is the size, in bytes, of a keystream block. Block counters
+	// passed to XORIC count blocks of this size.
+	BlockSize = salsa.BlockSize
+)
+
+// Stream returns the first l bytes of the Salsa20/8 keystream for nonce and
+// key.
+func Stream(l int, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	out := make([]byte, l)
+	salsa.XORKeyStream(out, out, nonce, 0, key, 8)
+	return out
+}
+
+// XOR encrypts or decrypts message by XORing it with the Salsa20/8 keystream
+// for nonce and key, and returns the result. It is the caller's
+// responsibility to ensure that a nonce is never reused with the same key.
+func XOR(message []byte, nonce *[NonceSize]byte, key nacl.Key) []byte {
+	return XORIC(message, nonce, 0, key)
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning. Byte i of message is XORed with byte
+// counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce *[NonceSize]byte, counter uint64, key nacl.Key) []byte {
+	out := make([]byte, len(message))
+	salsa.XORKeyStream(out, message, nonce, counter, key, 8)
+	return out
+}
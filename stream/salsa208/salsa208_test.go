@@ -0,0 +1,54 @@
+package salsa208
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestESTREAM(t *testing.T) {
+	// The first and eighth blocks of keystream for eSTREAM's 256-bit key
+	// Set 1, vector 0: the key 0x80 0x00 ... 0x00 and an all zero nonce.
+	// Generated with libsodium's crypto_stream_salsa208.
+	const (
+		block0 = "b1f599e9b0d96df436ae31f5ef589565b92d245db5a1d4c7a78e5e8d0146f8a49d326c1a3bf50c052c9c8f114dc74972c4469591e31c9ed11927aa9871f38583"
+		block7 = "53bf865c66a344cfcd19177476a05aca5851cc45224b196abf3206d899e7fe3b13b3f028fa849b5564561a9181ea69e512bc34da29180cdf6811e40a9a06a8d1"
+	)
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	key[0] = 0x80
+	stream := Stream(8*BlockSize, &nonce, &key)
+	if got := hex.EncodeToString(stream[:BlockSize]); got != block0 {
+		t.Errorf("Stream block 0: got %s, want %s", got, block0)
+	}
+	if got := hex.EncodeToString(stream[7*BlockSize:]); got != block7 {
+		t.Errorf("Stream block 7: got %s, want %s", got, block7)
+	}
+	if got := hex.EncodeToString(XORIC(make([]byte, BlockSize), &nonce, 7, &key)); got != block7 {
+		t.Errorf("XORIC(7): got %s, want %s", got, block7)
+	}
+	if got := XORIC(make([]byte, 3*BlockSize+5), &nonce, 2, &key); !bytes.Equal(got, stream[2*BlockSize:5*BlockSize+5]) {
+		t.Errorf("XORIC(2) does not continue Stream")
+	}
+}
+
+func TestXOR(t *testing.T) {
+	// Generated with libsodium's crypto_stream_salsa208_xor, with the nonce
+	// 0x00..0x07, the key 0x20..0x3f and the message 0x00..0x63.
+	const want = "c0a1040ae4604375c12f7cf2347a56143a7515488be1026e6dd03748b4adb50133cae04edadf29fffb16aa34d14dc53612e013e244219feac74cdf724e9cabc7e9cd151790e75d895ce0f220cad0f0d884bc0170662cc46e474d7b5479c76585d131d067"
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	if got := hex.EncodeToString(XOR(message, &nonce, &key)); got != want {
+		t.Errorf("XOR: got %s, want %s", got, want)
+	}
+}
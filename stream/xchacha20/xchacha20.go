@@ -0,0 +1,69 @@
+// Package xchacha20 implements the XChaCha20 stream cipher, a version of
+// ChaCha20 with a 24-byte nonce. It is interoperable with libsodium's
+// crypto_stream_xchacha20.
+//
+// Nonces are long enough that randomly generated nonces have negligible risk
+// of collision.
+//
+// XChaCha20 derives a subkey from the key and the first 16 bytes of the
+// nonce with HChaCha20, then uses the original ChaCha20 from the
+// stream/chacha20 package, with its 64-bit block counter, with the subkey and
+// the last 8 bytes of the nonce. Note that libsodium's
+// crypto_aead_xchacha20poly1305_ietf and golang.org/x/crypto/chacha20 use a
+// 32-bit counter instead; the keystreams are the same for the first 256 GiB.
+//
+// This package does not authenticate the data it encrypts. To detect
+// tampering, use the secretbox/xchacha20poly1305 package instead.
+package xchacha20 // import "github.com/kevinburke/nacl/stream/xchacha20"
+
+import (
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/stream/chacha20"
+	chacha "golang.org/x/crypto/chacha20"
+)
+
+const (
+	// KeySize is the size, in bytes, of an XChaCha20 key.
+	KeySize = 32
+	// NonceSize is the size, in bytes, of an XChaCha20 nonce.
+	NonceSize = 24
+	// BlockSize is the size, in bytes, of a keystream block. Block counters
+	// passed to XORIC count blocks of this size.
+	BlockSize = chacha20.BlockSize
+)
+
+// Stream returns the first l bytes of the XChaCha20 keystream for nonce and
+// key.
+func Stream(l int, nonce nacl.Nonce, key nacl.Key) []byte {
+	subKey, subNonce := setup(nonce, key)
+	return chacha20.Stream(l, subNonce, subKey)
+}
+
+// XOR encrypts or decrypts message by XORing it with the XChaCha20 keystream
+// for nonce and key, and returns the result. It is the caller's
+// responsibility to ensure that a nonce is never reused with the same key.
+func XOR(message []byte, nonce nacl.Nonce, key nacl.Key) []byte {
+	return XORIC(message, nonce, 0, key)
+}
+
+// XORIC is like XOR, but starts the keystream at block number counter rather
+// than at the beginning, the same as libsodium's
+// crypto_stream_xchacha20_xor_ic. Byte i of message is XORed with byte
+// counter*BlockSize+i of the keystream.
+func XORIC(message []byte, nonce nacl.Nonce, counter uint64, key nacl.Key) []byte {
+	subKey, subNonce := setup(nonce, key)
+	return chacha20.XORIC(message, subNonce, counter, subKey)
+}
+
+// setup returns the ChaCha20 key and nonce for an XChaCha20 nonce and key.
+func setup(nonce nacl.Nonce, key nacl.Key) (nacl.Key, *[chacha20.NonceSize]byte) {
+	hk, err := chacha.HChaCha20(key[:], nonce[:16])
+	if err != nil {
+		panic(err)
+	}
+	subKey := new([nacl.KeySize]byte)
+	copy(subKey[:], hk)
+	subNonce := new([chacha20.NonceSize]byte)
+	copy(subNonce[:], nonce[16:])
+	return subKey, subNonce
+}
@@ -0,0 +1,77 @@
+package xchacha20
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	chacha "golang.org/x/crypto/chacha20"
+)
+
+// Generated with libsodium's crypto_stream_xchacha20_xor_ic, with the
+// nonce 0x00..0x17, the key 0x20..0x3f and the message 0x00..0x63.
+var xorICTests = []struct {
+	counter uint64
+	out     string
+}{
+	{0, "7e0c5ac220f3482e5d7f8a29e7ff24291fd80776f36f89a75776c47d2e5c1a43aec3a8e20a63600f0014a34886d4427e174539a421827680b99493352f9ee3b4f748c6b2717d411e5fa701761f1e75b1484e4f388cbb11c0d457a83928d458afd8860d43"},
+	{1, "b70886f2313d015e1fe741365f5e35f1080e0f78ccfb51809417e879689418ef98c64d03a9443dde80941e8a0b02c22a3b681325747661161dad1f5a32c9b5e87948a21725d60b63ce89c237abdbd7ae7400f64f6f9036fd3aa3c62f7f8d03d81cc58b89"},
+	// The counter is 64 bits, and carries into its upper half.
+	{0xffffffff, "07a0610f28aff3a0ccddaabdbcf900af2af9f68cd34e4468f9fa1c0c9c9bab8661b407d2436ed5ed56c74fcbd4438bc1b9eee1fc74a3cb9581cdfb7b11c541418e735cfab7254d4e69eb0450b5b8491a2a17f29a37736a8c547d2d3a28a9799adb0c23a8"},
+}
+
+func TestXORIC(t *testing.T) {
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	for i := range key {
+		key[i] = byte(32 + i)
+	}
+	message := make([]byte, 100)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	for _, tt := range xorICTests {
+		if got := hex.EncodeToString(XORIC(message, &nonce, tt.counter, &key)); got != tt.out {
+			t.Errorf("XORIC(%#x): got %s, want %s", tt.counter, got, tt.out)
+		}
+	}
+
+	stream := Stream(4*BlockSize, &nonce, &key)
+	ciphertext := XOR(message, &nonce, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[i] {
+			t.Fatalf("XOR: byte %d is not message XOR Stream", i)
+		}
+	}
+	ciphertext = XORIC(message, &nonce, 2, &key)
+	for i := range message {
+		if ciphertext[i] != message[i]^stream[2*BlockSize+i] {
+			t.Fatalf("XORIC(2): byte %d is not message XOR Stream", i)
+		}
+	}
+}
+
+func TestXCryptoChaCha20(t *testing.T) {
+	// golang.org/x/crypto/chacha20 implements XChaCha20 with a 32-bit block
+	// counter, which gives the same keystream until the counter wraps.
+	var nonce [NonceSize]byte
+	var key [KeySize]byte
+	for i := range nonce {
+		nonce[i] = byte(0x40 + i)
+	}
+	for i := range key {
+		key[i] = byte(0x80 + i)
+	}
+	c, err := chacha.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 1000)
+	c.XORKeyStream(want, want)
+	if got := Stream(len(want), &nonce, &key); !bytes.Equal(got, want) {
+		t.Errorf("Stream does not match golang.org/x/crypto/chacha20")
+	}
+}